@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/input"
+	"github.com/user/tcrs/internal/textwidth"
+	"github.com/user/tcrs/internal/tui"
+	"golang.org/x/term"
+)
+
+var (
+	editDate   string
+	editEditor bool
+)
+
+var editCmd = &cobra.Command{
+	Use:     "edit",
+	Aliases: []string{"tui"},
+	Short:   "Edit a week's timecard in the terminal",
+	Long: `Edit a week's timecard in a full-screen grid.
+
+Move between cells with the arrow keys (or hjkl, Tab), type hours into a
+cell and press Enter, or press n to write the cell's note. Press a to add
+a row from a searchable list of your projects and activities, x to remove
+the current row. Daily totals update as you type.
+
+Press s to review the changed days and save them, q to quit. Days marked
+* in the header are weekends or days off in the holiday calendar.
+
+With --editor the week is opened in $VISUAL or $EDITOR as a YAML
+document instead, one block per project/activity with the days keyed by
+weekday:
+
+  # Project Alpha / Development
+  - project_id: "12345"
+    activity_id: "678"
+    mon: 8
+    tue: {hours: 7.5, note: code review}
+
+Save and quit the editor to see the changed days and confirm saving
+them. A document that does not parse is reopened after you confirm.`,
+	Args: cobra.NoArgs,
+	Run:  runEdit,
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+	editCmd.Flags().StringVar(&editDate, "date", "", "any date in the week: YYYY-MM-DD, 2025-W03, last-week, -2w, ... (default: this week)")
+	editCmd.Flags().BoolVar(&editEditor, "editor", false, "edit the week as YAML in $EDITOR")
+}
+
+func runEdit(cmd *cobra.Command, args []string) {
+	isTerminal := term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+	if IsJSON() || (!editEditor && !isTerminal) {
+		printError("Cannot edit", fmt.Errorf("tcrs edit needs a terminal; use tcrs save --file to save from a script"))
+		os.Exit(1)
+	}
+
+	userID := findLoggedInUser()
+	if userID == "" {
+		printError("Not logged in", fmt.Errorf("please login first with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	c, err := client.NewClient(userID, cfg)
+	if err != nil {
+		printError("Failed to create client", err)
+		os.Exit(1)
+	}
+
+	if !c.IsLoggedIn() {
+		printError("Session expired", fmt.Errorf("please login again with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	date := resolveWeek(editDate)
+	tc, err := c.GetWeekTimecard(date)
+	if err != nil {
+		printError("Failed to get week timecard", err)
+		os.Exit(1)
+	}
+	if tc.Locked {
+		printError("Cannot edit", fmt.Errorf("week starting %s is read-only: %w", date, client.ErrWeekLocked))
+		os.Exit(1)
+	}
+	// Saving refuses such a week, so don't let the edits be lost
+	if tc.Misaligned() {
+		printError("Cannot edit", fmt.Errorf("%w: requested %s but the server's week starts %s (check TCRS_WEEK_START)", client.ErrWeekMisaligned, date, tc.Dates[0]))
+		os.Exit(1)
+	}
+	pa, err := c.GetProjectsAndActivities(date)
+	if err != nil {
+		printError("Failed to get projects", err)
+		os.Exit(1)
+	}
+
+	if editEditor {
+		editInEditor(c, date, tc, pa)
+		return
+	}
+
+	week := tui.Week{
+		StartDate: date,
+		Status:    string(tc.Status),
+		Labels:    editLabels(tc),
+		Choices:   editChoices(pa),
+		Validate: func(entries []client.SaveEntry) error {
+			return client.ValidateEntries(date, entries, cfg.MaxDailyHours, cfg.HoursStep)
+		},
+	}
+	names := make(map[string]string, len(week.Choices))
+	for _, choice := range week.Choices {
+		names[choice.ProjectID+"$"+choice.ActivityID] = choice.ActivityName
+	}
+	for i, entry := range tc.SaveEntries() {
+		activityName := tc.Entries[i].ActivityName
+		if name := names[entry.ProjectID+"$"+entry.ActivityID]; name != "" {
+			activityName = name
+		}
+		week.Rows = append(week.Rows, tui.Row{
+			ProjectName:  tc.Entries[i].ProjectName,
+			ActivityName: activityName,
+			Entry:        entry,
+		})
+	}
+
+	entries, ok, err := tui.Run(os.Stdin, os.Stdout, week)
+	if err != nil {
+		printError("Editor failed", err)
+		os.Exit(1)
+	}
+	if !ok {
+		fmt.Println("Nothing saved")
+		return
+	}
+	saveSingleWeek(c, input.Week{StartDate: date, Entries: entries})
+}
+
+// editInEditor writes the week as a YAML document, opens it in the
+// user's editor and saves the result after showing the changed days.
+// Documents that do not parse or validate are reopened on request.
+func editInEditor(c *client.Client, date string, tc *client.WeekTimecard, pa *client.ProjectsAndActivities) {
+	var days [7]time.Time
+	for i := range days {
+		days[i], _ = tc.ColumnDate(i)
+	}
+	original := tc.SaveEntries()
+	choices := editChoices(pa)
+	labels := make(map[string]string, len(choices)+len(original))
+	activityNames := make(map[string]string, len(choices))
+	for _, choice := range choices {
+		key := choice.ProjectID + "$" + choice.ActivityID
+		labels[key] = choice.ProjectName + " / " + choice.ActivityName
+		activityNames[key] = choice.ActivityName
+	}
+
+	named := make([]input.NamedEntry, 0, len(original))
+	for i, entry := range original {
+		key := entry.ProjectID + "$" + entry.ActivityID
+		activityName := tc.Entries[i].ActivityName
+		if name := activityNames[key]; name != "" {
+			activityName = name
+		}
+		named = append(named, input.NamedEntry{SaveEntry: entry, ProjectName: tc.Entries[i].ProjectName, ActivityName: activityName})
+		labels[key] = tc.Entries[i].ProjectName + " / " + activityName
+	}
+
+	doc, err := input.FormatWeekDocument(days, string(tc.Status), named, pa)
+	if err != nil {
+		printError("Failed to write week document", err)
+		os.Exit(1)
+	}
+	f, err := os.CreateTemp("", "tcrs-week-*.yaml")
+	if err != nil {
+		printError("Failed to create temporary file", err)
+		os.Exit(1)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	_, err = f.Write(doc)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		printError("Failed to write week document", err)
+		os.Exit(1)
+	}
+
+	var entries []client.SaveEntry
+	for {
+		if err := runEditor(path); err != nil {
+			printError("Editor failed", err)
+			os.Exit(1)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			printError("Failed to read week document", err)
+			os.Exit(1)
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			fmt.Println("Empty document, nothing saved")
+			return
+		}
+
+		entries, err = editedEntries(data, days, pa, original)
+		if err == nil {
+			err = client.ValidateEntries(date, entries, cfg.MaxDailyHours, cfg.HoursStep)
+		}
+		if err == nil {
+			break
+		}
+		printError("Invalid week document", err)
+		if !confirm("Edit again?") {
+			fmt.Println("Nothing saved")
+			return
+		}
+	}
+
+	changes := client.DiffEntries(original, entries)
+	if len(changes) == 0 {
+		fmt.Println("No changes")
+		return
+	}
+	printEntryChanges(changes, labels, editLabels(tc))
+	if !confirm(fmt.Sprintf("Save %d changed day(s) to TCRS?", len(changes))) {
+		fmt.Println("Nothing saved")
+		return
+	}
+	saveSingleWeek(c, input.Week{StartDate: date, Entries: entries})
+}
+
+// editedEntries reads an edited week document into entries, keeping the
+// progress values of the saved entries.
+func editedEntries(data []byte, days [7]time.Time, pa *client.ProjectsAndActivities, original []client.SaveEntry) ([]client.SaveEntry, error) {
+	rows, err := input.ParseWeekDocument(data, days, pa)
+	if err != nil {
+		return nil, err
+	}
+	catalog := func(string) (*client.ProjectsAndActivities, error) { return pa, nil }
+	weeks, err := input.ResolveSheet(rows, catalog, cfg.WeekStart)
+	if err != nil {
+		return nil, err
+	}
+	entries := []client.SaveEntry{}
+	if len(weeks) > 0 {
+		entries = weeks[0].Entries
+	}
+
+	saved := make(map[string]client.SaveEntry, len(original))
+	for _, entry := range original {
+		saved[entry.ProjectID+"$"+entry.ActivityID] = entry
+	}
+	for i, entry := range entries {
+		old, ok := saved[entry.ProjectID+"$"+entry.ActivityID]
+		if !ok {
+			continue
+		}
+		entries[i].Progress = old.Progress
+		for d := range entry.Days {
+			if d < len(old.Days) {
+				entries[i].Days[d].Progress = old.Days[d].Progress
+			}
+		}
+	}
+	return entries, nil
+}
+
+// runEditor opens path in $VISUAL or $EDITOR, or vi (notepad on
+// Windows), and waits for it to exit.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// printEntryChanges lists changed days with their old and new hours and
+// notes.
+func printEntryChanges(changes []client.DayChange, names map[string]string, days [7]string) {
+	fmt.Printf("%-40s %-11s %s\n", "Project/Activity", "Day", "Change")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, c := range changes {
+		name := names[c.ProjectID+"$"+c.ActivityID]
+		if name == "" {
+			name = c.ProjectID + " / " + c.ActivityID
+		}
+		change := ""
+		if c.Before.Hours.Float() != c.After.Hours.Float() {
+			change = fmt.Sprintf("%s -> %s", hoursOrDash(c.Before.Hours), hoursOrDash(c.After.Hours))
+		}
+		if strings.TrimSpace(c.Before.Note) != strings.TrimSpace(c.After.Note) {
+			change += fmt.Sprintf("  note %q -> %q", c.Before.Note, c.After.Note)
+		}
+		fmt.Printf("%s %s %s\n", textwidth.Pad(textwidth.Truncate(name, 40, "..."), 40), textwidth.Pad(days[c.Day], 11), strings.TrimSpace(change))
+	}
+	fmt.Println()
+}
+
+func hoursOrDash(h client.Hours) string {
+	if h.IsEmpty() {
+		return "-"
+	}
+	return h.String()
+}
+
+// editLabels returns the column headers of the week, e.g. "Mon 10/12",
+// with weekends and days off in the holiday calendar marked "*".
+func editLabels(tc *client.WeekTimecard) [7]string {
+	var labels [7]string
+	cal := loadCalendar()
+	for i, label := range dates.WeekdayLabels(cfg.Locale, cfg.WeekStart) {
+		labels[i] = label
+		d, ok := tc.ColumnDate(i)
+		if !ok {
+			continue
+		}
+		labels[i] = dates.WeekdayLabel(cfg.Locale, d.Weekday()) + " " + d.Format("01/02")
+		if !cal.IsWorkday(d) {
+			labels[i] = "*" + labels[i]
+		}
+	}
+	return labels
+}
+
+// editChoices lists the projects and bookable activities that can be
+// added as rows. Only leaf activities are offered when a project marks
+// them.
+func editChoices(pa *client.ProjectsAndActivities) []tui.Choice {
+	var choices []tui.Choice
+	for _, p := range pa.Projects {
+		hasLeaves := false
+		for _, a := range p.Activities {
+			hasLeaves = hasLeaves || a.IsBottom
+		}
+		if len(p.Activities) == 0 {
+			choices = append(choices, tui.Choice{ProjectID: p.ID, ProjectName: p.Name})
+		}
+		for _, a := range p.Activities {
+			if hasLeaves && !a.IsBottom {
+				continue
+			}
+			name := a.FullName
+			if name == "" {
+				name = a.Name
+			}
+			choices = append(choices, tui.Choice{
+				ProjectID:    p.ID,
+				ProjectName:  p.Name,
+				ActivityID:   a.ID,
+				ActivityName: name,
+				WBS:          a.WBS,
+			})
+		}
+	}
+	return choices
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/export"
+)
+
+var (
+	exportFrom   string
+	exportTo     string
+	exportMonth  string
+	exportFormat string
+	exportOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export timecards to CSV, XLSX or JSON Lines",
+	Long: `Export the timecards of a date range with one row per day per
+project/activity: date, week start, project, activity full name, hours,
+note and week status.
+
+The format is picked from the --output extension (.csv, .xlsx, .jsonl)
+or set with --format; without --output, CSV is written to stdout.
+
+XLSX workbooks have an "Entries" sheet with the rows, ready for a pivot
+table, and a "Totals" sheet with the hours per project and activity.`,
+	Run: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFrom, "from", "", "first date of the range (default: first day of this month)")
+	exportCmd.Flags().StringVar(&exportTo, "to", "", "last date of the range (default: today)")
+	exportCmd.Flags().StringVar(&exportMonth, "month", "", "export a calendar month: YYYY-MM")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "output format: csv, xlsx or jsonl (default: from --output, else csv)")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "-", "output file, or - for stdout")
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	from, to, err := resolveRange(exportFrom, exportTo, exportMonth, time.Now())
+	if err != nil {
+		printError("Invalid date range", err)
+		os.Exit(1)
+	}
+	format := exportFormat
+	if format == "" {
+		format = export.FormatFromPath(exportOutput)
+	}
+	if err := export.CheckFormat(format); err != nil {
+		printError("Invalid format", err)
+		os.Exit(1)
+	}
+
+	userID := findLoggedInUser()
+	if userID == "" {
+		printError("Not logged in", fmt.Errorf("please login first with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	c, err := client.NewClient(userID, cfg)
+	if err != nil {
+		printError("Failed to create client", err)
+		os.Exit(1)
+	}
+
+	if !c.IsLoggedIn() {
+		printError("Session expired", fmt.Errorf("please login again with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	if IsVerbose() {
+		fmt.Fprintf(os.Stderr, "Fetching weeks from %s to %s...\n", from.Format(dates.Layout), to.Format(dates.Layout))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	weeks, err := c.GetWeeks(ctx, from, to)
+	if err != nil {
+		printError("Failed to get week timecards", err)
+		os.Exit(1)
+	}
+
+	// Activity full names come from the project catalog; the week page
+	// only shows the short name.
+	catalog, err := c.GetProjectsAndActivities(to.Format(dates.Layout))
+	if err != nil && IsVerbose() {
+		fmt.Fprintf(os.Stderr, "Could not load activity names: %v\n", err)
+	}
+	rows := export.Rows(weeks, from, to, export.NewActivityNames(catalog))
+
+	var out io.Writer = os.Stdout
+	if exportOutput != "-" {
+		file, err := os.Create(exportOutput)
+		if err != nil {
+			printError("Failed to create output file", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := export.Write(out, rows, format); err != nil {
+		printError("Failed to write export", err)
+		os.Exit(1)
+	}
+
+	if exportOutput != "-" {
+		fmt.Fprintf(os.Stderr, "Exported %d rows to %s\n", len(rows), exportOutput)
+	}
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/calendar"
+	"github.com/user/tcrs/internal/dates"
+)
+
+var (
+	holidaysKind  string
+	holidaysName  string
+	holidaysFrom  string
+	holidaysTo    string
+	holidaysMonth string
+	holidaysAll   bool
+)
+
+var holidaysCmd = &cobra.Command{
+	Use:   "holidays",
+	Short: "Manage holidays, days off and leave",
+	Long: `Manage the calendar of public holidays, company days off, personal
+leave and make-up workdays.
+
+Days off are skipped by "tcrs missing" and "tcrs submit" and marked in
+"tcrs week". Make-up workdays (e.g. Taiwan's Saturday 補班) are treated
+as working days even though they fall on a weekend.
+
+Kinds: holiday, company, leave, workday.`,
+}
+
+var holidaysImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import days from an ICS or YAML file",
+	Long: `Import days from an iCalendar (.ics) or YAML file into the calendar.
+
+Every event of an ICS file becomes a day of --kind (default: holiday);
+events named 補班 or "make-up workday" become workdays. A YAML file is a
+list of days:
+
+  - date: 2025-01-01
+    name: New Year's Day
+  - date: 2025-02-08
+    kind: workday
+    name: 補班
+
+Imported days replace existing days with the same date.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runHolidaysImport,
+}
+
+var holidaysListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List calendar days",
+	Run:   runHolidaysList,
+}
+
+var holidaysAddCmd = &cobra.Command{
+	Use:   "add <date>",
+	Short: "Add a day to the calendar",
+	Args:  cobra.ExactArgs(1),
+	Run:   runHolidaysAdd,
+}
+
+var holidaysRemoveCmd = &cobra.Command{
+	Use:   "remove <date>",
+	Short: "Remove a day from the calendar",
+	Args:  cobra.ExactArgs(1),
+	Run:   runHolidaysRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(holidaysCmd)
+	holidaysCmd.AddCommand(holidaysImportCmd, holidaysListCmd, holidaysAddCmd, holidaysRemoveCmd)
+
+	holidaysImportCmd.Flags().StringVar(&holidaysKind, "kind", "holiday", "kind of the imported ICS events")
+	holidaysAddCmd.Flags().StringVar(&holidaysKind, "kind", "holiday", "kind of day: holiday, company, leave or workday")
+	holidaysAddCmd.Flags().StringVar(&holidaysName, "name", "", "description of the day")
+	holidaysListCmd.Flags().StringVar(&holidaysFrom, "from", "", "first date to list (default: first day of this month)")
+	holidaysListCmd.Flags().StringVar(&holidaysTo, "to", "", "last date to list (default: end of the month of --from)")
+	holidaysListCmd.Flags().StringVar(&holidaysMonth, "month", "", "list a calendar month: YYYY-MM")
+	holidaysListCmd.Flags().BoolVar(&holidaysAll, "all", false, "list every day in the calendar")
+}
+
+// loadCalendar loads the calendar file and adds the days in TCRS_HOLIDAYS
+// as holidays. It exits with an error if the file cannot be read.
+func loadCalendar() *calendar.Calendar {
+	cal := loadStoredCalendar()
+	for _, date := range cfg.Holidays {
+		t, err := time.Parse(dates.Layout, date)
+		if err != nil {
+			continue
+		}
+		if _, ok := cal.Lookup(t); !ok {
+			cal.Add(calendar.Day{Date: date, Kind: calendar.KindHoliday})
+		}
+	}
+	return cal
+}
+
+func runHolidaysImport(cmd *cobra.Command, args []string) {
+	kind, err := calendar.ParseKind(holidaysKind)
+	if err != nil {
+		printError("Invalid kind", err)
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		printError("Failed to read file", err)
+		os.Exit(1)
+	}
+
+	var days []calendar.Day
+	switch strings.ToLower(filepath.Ext(args[0])) {
+	case ".ics", ".ical", ".ifb":
+		days, err = calendar.ParseICS(data, kind)
+	default:
+		days, err = calendar.ParseYAML(data)
+	}
+	if err != nil {
+		printError("Failed to parse calendar", err)
+		os.Exit(1)
+	}
+
+	cal := loadStoredCalendar()
+	cal.Add(days...)
+	saveCalendar(cal)
+
+	if IsJSON() {
+		result := map[string]interface{}{
+			"success":  true,
+			"imported": len(days),
+			"total":    len(cal.Days),
+		}
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("Imported %d days (%d in calendar)\n", len(days), len(cal.Days))
+	}
+}
+
+func runHolidaysList(cmd *cobra.Command, args []string) {
+	cal := loadCalendar()
+	days := cal.Days
+	if !holidaysAll {
+		from, to, err := resolveRange(holidaysFrom, holidaysTo, holidaysMonth, time.Now())
+		if err != nil {
+			printError("Invalid date range", err)
+			os.Exit(1)
+		}
+		if holidaysTo == "" && holidaysMonth == "" {
+			// Upcoming days off are worth listing too, so run to month end
+			to = time.Date(from.Year(), from.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		}
+		days = cal.Between(from, to)
+	}
+
+	if IsJSON() {
+		data, _ := json.MarshalIndent(map[string]interface{}{"days": days}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(days) == 0 {
+		fmt.Println("No days found")
+		return
+	}
+	for _, d := range days {
+		t, _ := time.Parse(dates.Layout, d.Date)
+		label := dates.WeekdayLabel(cfg.Locale, t.Weekday())
+		fmt.Printf("%s %s  %-8s %s\n", d.Date, label, d.Kind, d.Name)
+	}
+}
+
+func runHolidaysAdd(cmd *cobra.Command, args []string) {
+	kind, err := calendar.ParseKind(holidaysKind)
+	if err != nil {
+		printError("Invalid kind", err)
+		os.Exit(1)
+	}
+	date, err := dates.ParseOn(args[0], time.Now(), cfg.WeekStart)
+	if err != nil {
+		printError("Invalid date", err)
+		os.Exit(1)
+	}
+
+	day := calendar.Day{Date: date.Format(dates.Layout), Kind: kind, Name: holidaysName}
+	cal := loadStoredCalendar()
+	cal.Add(day)
+	saveCalendar(cal)
+
+	if IsJSON() {
+		data, _ := json.MarshalIndent(map[string]interface{}{"success": true, "day": day}, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("Added %s as %s\n", day.Date, day.Kind)
+	}
+}
+
+func runHolidaysRemove(cmd *cobra.Command, args []string) {
+	date, err := dates.ParseOn(args[0], time.Now(), cfg.WeekStart)
+	if err != nil {
+		printError("Invalid date", err)
+		os.Exit(1)
+	}
+
+	cal := loadStoredCalendar()
+	if !cal.Remove(date.Format(dates.Layout)) {
+		printError("Not in calendar", fmt.Errorf("no day on %s", date.Format(dates.Layout)))
+		os.Exit(1)
+	}
+	saveCalendar(cal)
+
+	if IsJSON() {
+		data, _ := json.MarshalIndent(map[string]interface{}{"success": true, "date": date.Format(dates.Layout)}, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("Removed %s\n", date.Format(dates.Layout))
+	}
+}
+
+// loadStoredCalendar loads only the calendar file, without TCRS_HOLIDAYS,
+// for commands that write it back.
+func loadStoredCalendar() *calendar.Calendar {
+	cal, err := calendar.Load(cfg.CalendarFile())
+	if err != nil {
+		printError("Failed to load calendar", err)
+		os.Exit(1)
+	}
+	return cal
+}
+
+// saveCalendar writes the calendar file, exiting on failure.
+func saveCalendar(cal *calendar.Calendar) {
+	if err := cal.Save(cfg.CalendarFile()); err != nil {
+		printError("Failed to save calendar", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/input"
+	"github.com/user/tcrs/internal/textwidth"
+	"github.com/user/tcrs/internal/tracker"
+)
+
+var (
+	importFormat string
+	importMap    []string
+	importYes    bool
+	importDryRun bool
+
+	importMapping   string
+	importRound     float64
+	importRoundMode string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import hours from a tracking sheet or time-tracker export",
+	Long: `Import hours from your own tracking sheet into TCRS.
+
+Each row of the sheet is one day of one project/activity with the
+columns date, project, activity, hours and note. Projects and activities
+may be given by ID or by name; names are looked up in the project list of
+each week. Rows are grouped into weeks and laid over what is already in
+TCRS: imported days replace the same project/activity's day, everything
+else is kept.
+
+Columns are found by their header. If your sheet uses other headers, map
+them with --map (or TCRS_IMPORT_COLUMNS), by header name or 1-based
+column number:
+
+  tcrs import hours.xlsx --map date=Day --map project=Customer --map hours=5
+
+Time-tracker exports are read with --format toggl or clockify (detailed
+report CSV) or timewarrior (timew export JSON). Their projects, tags and
+descriptions are mapped to TCRS projects and activities by the rules in
+a mapping file (--mapping, default ~/.tcrs/mapping.yaml). Rules without
+a source (or with "source: tracker") apply here; "source: git" and
+"source: ics" rules are for tcrs suggest:
+
+  rules:
+    - match: backend          # project or tag name
+      project: "12345"
+      activity: Development
+    - contains: standup       # text in the description
+      project: Internal
+      activity: Meeting
+
+Tracked time is summed per day and rule, rounded with --round (default:
+TCRS_HOURS_STEP) and --round-mode, and descriptions become the note.
+
+A preview of the rows is shown and confirmed before anything is saved;
+--yes skips the question and --dry-run only shows the preview.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&importFormat, "format", "", "input format: csv, tsv, xlsx, toggl, clockify or timewarrior (default: from file extension)")
+	importCmd.Flags().StringArrayVar(&importMap, "map", nil, "map a field to a column: field=header or field=N (repeatable)")
+	importCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "save without asking for confirmation")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "show the preview without saving")
+	importCmd.Flags().StringVar(&importMapping, "mapping", "", "rules mapping tracker projects and tags to TCRS (default: ~/.tcrs/mapping.yaml)")
+	importCmd.Flags().Float64Var(&importRound, "round", -1, "round tracked hours per day to this step, 0 to keep (default: TCRS_HOURS_STEP)")
+	importCmd.Flags().StringVar(&importRoundMode, "round-mode", tracker.RoundNearest, "rounding: nearest, up or down")
+}
+
+func runImport(cmd *cobra.Command, args []string) {
+	mapping, err := input.ParseColumnMapping(append(append([]string{}, cfg.ImportColumns...), importMap...))
+	if err != nil {
+		printError("Invalid column mapping", err)
+		os.Exit(1)
+	}
+	if IsJSON() && !importYes && !importDryRun {
+		printError("Confirmation required", fmt.Errorf("use --yes or --dry-run together with --json"))
+		os.Exit(1)
+	}
+
+	path := args[0]
+	format := importFormat
+	if format == "" {
+		format = input.FormatFromPath(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		printError("Failed to read file", err)
+		os.Exit(1)
+	}
+	rows, err := readImportRows(data, format, mapping)
+	if err != nil {
+		printError("Failed to parse input", err)
+		os.Exit(1)
+	}
+	if len(rows) == 0 {
+		printError("Nothing to import", fmt.Errorf("%s has no rows", path))
+		os.Exit(1)
+	}
+
+	userID := findLoggedInUser()
+	if userID == "" {
+		printError("Not logged in", fmt.Errorf("please login first with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	c, err := client.NewClient(userID, cfg)
+	if err != nil {
+		printError("Failed to create client", err)
+		os.Exit(1)
+	}
+
+	if !c.IsLoggedIn() {
+		printError("Session expired", fmt.Errorf("please login again with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	weeks, err := input.ResolveSheet(rows, c.GetProjectsAndActivities, cfg.WeekStart)
+	if err != nil {
+		printError("Failed to resolve projects", err)
+		os.Exit(1)
+	}
+
+	merged, kept := prepareWeeks(c, weeks)
+
+	if IsJSON() && importDryRun {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"success": true,
+			"dry_run": true,
+			"rows":    len(rows),
+			"weeks":   merged,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if !IsJSON() {
+		printImportPreview(rows, merged, kept)
+	}
+	if importDryRun {
+		return
+	}
+	if !importYes && !confirm(fmt.Sprintf("Save %d week(s) to TCRS?", len(merged))) {
+		fmt.Println("Import cancelled")
+		return
+	}
+
+	if len(merged) == 1 {
+		saveSingleWeek(c, merged[0])
+		return
+	}
+	saveWeeks(c, merged)
+}
+
+// readImportRows reads a spreadsheet, or a time-tracker export mapped
+// with the rules of the mapping file.
+func readImportRows(data []byte, format string, columns input.ColumnMapping) ([]input.SheetRow, error) {
+	if !tracker.IsFormat(format) {
+		return input.ReadSheet(data, format, columns)
+	}
+
+	rounding := tracker.Rounding{Step: importRound, Mode: importRoundMode}
+	if rounding.Step < 0 {
+		rounding.Step = cfg.HoursStep
+	}
+	if err := rounding.Validate(); err != nil {
+		return nil, err
+	}
+	path := importMapping
+	if path == "" {
+		path = cfg.MappingFile()
+	}
+	rules, err := tracker.LoadMapping(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mapping: %w", err)
+	}
+
+	spans, err := tracker.Parse(data, format)
+	if err != nil {
+		return nil, err
+	}
+	return tracker.Rows(spans, rules, rounding)
+}
+
+// printImportPreview lists the imported rows and, per week, how many
+// rows already in TCRS are kept.
+func printImportPreview(rows []input.SheetRow, weeks []input.Week, kept map[string]int) {
+	fmt.Printf("%-12s %-30s %-20s %7s  %s\n", "Date", "Project", "Activity", "Hours", "Note")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, row := range rows {
+		project := textwidth.Pad(textwidth.Truncate(row.Project, 30, "..."), 30)
+		activity := textwidth.Pad(textwidth.Truncate(row.Activity, 20, "..."), 20)
+		fmt.Printf("%-12s %s %s %7s  %s\n", row.Date.Format(dates.Layout), project, activity, row.Hours, row.Note)
+	}
+	fmt.Println()
+	for _, week := range weeks {
+		fmt.Printf("Week starting %s: %d rows, %d kept from TCRS\n", week.StartDate, len(week.Entries), kept[week.StartDate])
+	}
+	fmt.Println()
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+)
+
+var (
+	missingFrom  string
+	missingTo    string
+	missingMonth string
+	missingHours float64
+)
+
+// exitMissing is the exit status of tcrs missing when days are missing
+// hours, distinct from the status 1 of failures.
+const exitMissing = 2
+
+var missingCmd = &cobra.Command{
+	Use:   "missing",
+	Short: "List working days with missing hours",
+	Long: `List every working day in a date range with fewer hours than expected.
+
+Holidays and leave days in the calendar (see "tcrs holidays") and in
+TCRS_HOLIDAYS are skipped, and make-up workdays are checked, so the
+command can run from cron before payroll. Today and later days are only
+checked when --to names them. Exit status:
+
+  0  no day is missing hours
+  1  the check failed (not logged in, a week could not be fetched, ...)
+  2  some days are missing hours`,
+	Run: runMissing,
+}
+
+func init() {
+	rootCmd.AddCommand(missingCmd)
+	missingCmd.Flags().StringVar(&missingFrom, "from", "", "first date of the range (default: first day of this month)")
+	missingCmd.Flags().StringVar(&missingTo, "to", "", "last date of the range (default: yesterday)")
+	missingCmd.Flags().StringVar(&missingMonth, "month", "", "check a calendar month: YYYY-MM")
+	missingCmd.Flags().Float64Var(&missingHours, "hours", 0, "expected hours per working day (default: TCRS_EXPECTED_HOURS or 8)")
+}
+
+func runMissing(cmd *cobra.Command, args []string) {
+	from, to, err := resolveRange(missingFrom, missingTo, missingMonth, time.Now())
+	if err != nil {
+		printError("Invalid date range", err)
+		os.Exit(1)
+	}
+	// Today is still being filled in, so it is only checked when --to
+	// names it.
+	if missingTo == "" {
+		today, _ := dates.ParseOn("", time.Now(), cfg.WeekStart)
+		if yesterday := today.AddDate(0, 0, -1); to.After(yesterday) {
+			to = yesterday
+		}
+	}
+	expected := cfg.ExpectedHours
+	if missingHours > 0 {
+		expected = missingHours
+	}
+
+	userID := findLoggedInUser()
+	if userID == "" {
+		printError("Not logged in", fmt.Errorf("please login first with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	c, err := client.NewClient(userID, cfg)
+	if err != nil {
+		printError("Failed to create client", err)
+		os.Exit(1)
+	}
+
+	if !c.IsLoggedIn() {
+		printError("Session expired", fmt.Errorf("please login again with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	if IsVerbose() {
+		fmt.Fprintf(os.Stderr, "Fetching weeks from %s to %s...\n", from.Format(dates.Layout), to.Format(dates.Layout))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	weeks, err := c.GetWeeks(ctx, from, to)
+	if err != nil {
+		printError("Failed to get week timecards", err)
+		os.Exit(1)
+	}
+
+	cal := loadCalendar()
+	missing := make([]client.MissingDay, 0)
+	for _, week := range weeks {
+		missing = append(missing, week.MissingDays(expected, from, to, cal.IsWorkday)...)
+	}
+
+	if IsJSON() {
+		result := map[string]interface{}{
+			"from":     from.Format(dates.Layout),
+			"to":       to.Format(dates.Layout),
+			"expected": expected,
+			"complete": len(missing) == 0,
+			"missing":  missing,
+		}
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+	} else if len(missing) == 0 {
+		fmt.Printf("No missing hours from %s to %s\n", from.Format(dates.Layout), to.Format(dates.Layout))
+	} else {
+		for _, day := range missing {
+			d, _ := time.Parse(dates.Layout, day.Date)
+			fmt.Printf("%s %s  %5.1f / %.1f h\n", day.Date, dates.WeekdayLabel(cfg.Locale, d.Weekday()), day.Hours, day.Expected)
+		}
+		fmt.Printf("\n%d day(s) missing hours\n", len(missing))
+	}
+
+	if len(missing) > 0 {
+		os.Exit(exitMissing)
+	}
+}
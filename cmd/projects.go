@@ -4,24 +4,37 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
 )
 
-var projectsDate string
+var (
+	projectsDate string
+	projectsSort string
+	projectsTree bool
+)
 
 var projectsCmd = &cobra.Command{
 	Use:   "projects",
 	Short: "List projects and activities",
-	Long:  `List available projects and their activities for a given date.`,
-	Run:   runProjects,
+	Long: `List available projects and their activities for a given date.
+
+Projects are listed in the order the TCRS page shows them. Use --sort
+to order them by ID or name instead. Activities always keep page order
+and are shown as a tree following their WBS codes. With --json, --tree
+nests child activities under their parents instead of a flat list.`,
+	Run: runProjects,
 }
 
 func init() {
 	rootCmd.AddCommand(projectsCmd)
-	projectsCmd.Flags().StringVar(&projectsDate, "date", "", "date in YYYY-MM-DD format (default: today)")
+	projectsCmd.Flags().StringVar(&projectsDate, "date", "", "date: YYYY-MM-DD, today, yesterday, -1w, ... (default: today)")
+	projectsCmd.Flags().StringVar(&projectsSort, "sort", "", "sort projects by 'id' or 'name' (default: page order)")
+	projectsCmd.Flags().BoolVar(&projectsTree, "tree", false, "nest activities as a tree in JSON output")
 }
 
 func runProjects(cmd *cobra.Command, args []string) {
@@ -43,10 +56,12 @@ func runProjects(cmd *cobra.Command, args []string) {
 	}
 
 	// Use today's date if not specified
-	date := projectsDate
-	if date == "" {
-		date = time.Now().Format("2006-01-02")
+	parsed, err := dates.ParseOn(projectsDate, time.Now(), cfg.WeekStart)
+	if err != nil {
+		printError("Invalid date", err)
+		os.Exit(1)
 	}
+	date := parsed.Format(dates.Layout)
 
 	if IsVerbose() {
 		fmt.Printf("Fetching projects for %s...\n", date)
@@ -58,8 +73,25 @@ func runProjects(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if err := result.SortProjects(projectsSort); err != nil {
+		printError("Invalid sort", err)
+		os.Exit(1)
+	}
+
 	if IsJSON() {
-		data, _ := json.MarshalIndent(result, "", "  ")
+		var data []byte
+		if projectsTree {
+			trees := make([]client.ProjectTree, 0, len(result.Projects))
+			for _, proj := range result.Projects {
+				trees = append(trees, proj.Tree())
+			}
+			data, _ = json.MarshalIndent(map[string]interface{}{
+				"date":     result.Date,
+				"projects": trees,
+			}, "", "  ")
+		} else {
+			data, _ = json.MarshalIndent(result, "", "  ")
+		}
 		fmt.Println(string(data))
 	} else {
 		fmt.Printf("Projects for %s:\n", result.Date)
@@ -76,19 +108,25 @@ func runProjects(cmd *cobra.Command, args []string) {
 			if len(proj.Activities) == 0 {
 				fmt.Println("  No activities")
 			} else {
-				for _, act := range proj.Activities {
-					indent := ""
-					if act.IndentLevel > 0 {
-						indent = "    "
-					}
-					bottomMark := ""
-					if act.IsBottom {
-						bottomMark = " [leaf]"
-					}
-					fmt.Printf("  %s- %s (ID: %s)%s\n", indent, act.Name, act.ID, bottomMark)
-				}
+				printActivityTree(proj.Tree().Activities, 1)
 			}
 			fmt.Println()
 		}
 	}
 }
+
+// printActivityTree prints activity nodes indented by their depth.
+func printActivityTree(nodes []*client.ActivityNode, depth int) {
+	for _, node := range nodes {
+		name := node.Name
+		if node.WBS != "" {
+			name = node.WBS + " " + name
+		}
+		bottomMark := ""
+		if node.IsBottom {
+			bottomMark = " [leaf]"
+		}
+		fmt.Printf("%s- %s (ID: %s)%s\n", strings.Repeat("  ", depth), name, node.ID, bottomMark)
+		printActivityTree(node.Children, depth+1)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/report"
+	"github.com/user/tcrs/internal/textwidth"
+)
+
+var (
+	reportFrom    string
+	reportTo      string
+	reportMonth   string
+	reportGroupBy string
+	reportCSV     bool
+
+	reportConcurrency int
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize hours over a date range",
+	Long: `Summarize the hours recorded between two dates.
+
+Every week overlapping the range is fetched and its hours are added up
+by project, activity, week or day. Only days inside the range count, so
+a month that starts mid-week is reported exactly. Each row shows its
+share of the total.
+
+Use --month for a calendar month instead of --from and --to.`,
+	Run: runReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVar(&reportFrom, "from", "", "first date of the range (default: first day of this month)")
+	reportCmd.Flags().StringVar(&reportTo, "to", "", "last date of the range (default: today)")
+	reportCmd.Flags().StringVar(&reportMonth, "month", "", "report a calendar month: YYYY-MM")
+	reportCmd.Flags().StringVar(&reportGroupBy, "group-by", report.GroupByProject, "group rows by project, activity, week or day")
+	reportCmd.Flags().BoolVar(&reportCSV, "csv", false, "output in CSV format")
+	reportCmd.Flags().IntVar(&reportConcurrency, "concurrency", 0, "weeks to fetch at once (default: TCRS_CONCURRENCY or 4)")
+}
+
+func runReport(cmd *cobra.Command, args []string) {
+	from, to, err := resolveRange(reportFrom, reportTo, reportMonth, time.Now())
+	if err != nil {
+		printError("Invalid date range", err)
+		os.Exit(1)
+	}
+	if err := report.ValidateGroupBy(reportGroupBy); err != nil {
+		printError("Invalid report", err)
+		os.Exit(1)
+	}
+
+	userID := findLoggedInUser()
+	if userID == "" {
+		printError("Not logged in", fmt.Errorf("please login first with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	c, err := client.NewClient(userID, cfg)
+	if err != nil {
+		printError("Failed to create client", err)
+		os.Exit(1)
+	}
+
+	if !c.IsLoggedIn() {
+		printError("Session expired", fmt.Errorf("please login again with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	if reportConcurrency > 0 {
+		cfg.Concurrency = reportConcurrency
+	}
+	if IsVerbose() {
+		fmt.Fprintf(os.Stderr, "Fetching weeks from %s to %s...\n", from.Format(dates.Layout), to.Format(dates.Layout))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	weeks, err := c.GetWeeks(ctx, from, to)
+	if err != nil {
+		printError("Failed to get week timecards", err)
+		os.Exit(1)
+	}
+
+	result, err := report.Build(weeks, from, to, reportGroupBy)
+	if err != nil {
+		printError("Invalid report", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case IsJSON():
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+	case reportCSV:
+		writeReportCSV(result)
+	default:
+		printReport(result)
+	}
+}
+
+func printReport(r *report.Report) {
+	fmt.Printf("Report: %s to %s, by %s\n", r.From, r.To, r.GroupBy)
+	fmt.Println()
+
+	if len(r.Rows) == 0 {
+		fmt.Println("No hours recorded")
+		return
+	}
+
+	fmt.Printf("%-50s %10s %8s\n", "Name", "Hours", "Share")
+	fmt.Println("----------------------------------------------------------------------")
+	for _, row := range r.Rows {
+		name := textwidth.Pad(textwidth.Truncate(row.Label, 48, "..."), 50)
+		fmt.Printf("%s %10.2f %7.1f%%\n", name, row.Hours, row.Percent)
+	}
+	fmt.Println("----------------------------------------------------------------------")
+	fmt.Printf("%-50s %10.2f %7.1f%%\n", "Total", r.Total, 100.0)
+}
+
+func writeReportCSV(r *report.Report) {
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{r.GroupBy, "name", "hours", "percent"})
+	for _, row := range r.Rows {
+		w.Write([]string{
+			row.Key,
+			row.Label,
+			strconv.FormatFloat(row.Hours, 'f', -1, 64),
+			strconv.FormatFloat(row.Percent, 'f', 1, 64),
+		})
+	}
+	w.Write([]string{"total", "Total", strconv.FormatFloat(r.Total, 'f', -1, 64), "100.0"})
+	w.Flush()
+}
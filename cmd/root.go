@@ -2,10 +2,13 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/user/tcrs/internal/config"
+	"github.com/user/tcrs/internal/dates"
 )
 
 var (
@@ -31,7 +34,7 @@ Timecard Recording System (TCRS).
 It provides commands for:
   - Authentication (login, logout, status)
   - Querying projects and activities
-  - Viewing and saving weekly timecards`,
+  - Viewing, saving and submitting weekly timecards`,
 	Version: Version,
 }
 
@@ -71,3 +74,53 @@ func IsVerbose() bool {
 func IsJSON() bool {
 	return jsonOut
 }
+
+// resolveWeek parses a --date argument and returns the start of the week
+// containing it as YYYY-MM-DD. It exits with an error if the date is
+// invalid.
+func resolveWeek(arg string) string {
+	date, err := dates.ParseWeek(arg, time.Now(), cfg.WeekStart)
+	if err != nil {
+		printError("Invalid date", err)
+		os.Exit(1)
+	}
+	return date
+}
+
+// resolveRange resolves --from, --to and --month arguments into an
+// inclusive date range. Without arguments it is the current month up to
+// today.
+func resolveRange(fromArg, toArg, monthArg string, now time.Time) (time.Time, time.Time, error) {
+	if monthArg != "" {
+		if fromArg != "" || toArg != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("--month cannot be combined with --from or --to")
+		}
+		month, err := time.Parse("2006-01", monthArg)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid month %q (use YYYY-MM)", monthArg)
+		}
+		return month, month.AddDate(0, 1, -1), nil
+	}
+
+	today, _ := dates.ParseOn("", now, cfg.WeekStart)
+	from := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if fromArg != "" {
+		d, err := dates.ParseOn(fromArg, now, cfg.WeekStart)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = d
+	}
+	to := today
+	if toArg != "" {
+		d, err := dates.ParseOn(toArg, now, cfg.WeekStart)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = d
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--to %s is before --from %s", to.Format(dates.Layout), from.Format(dates.Layout))
+	}
+	return from, to, nil
+}
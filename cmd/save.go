@@ -3,24 +3,29 @@ package cmd
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/input"
 )
 
 var (
-	saveDate string
-	saveFile string
+	saveDate   string
+	saveFile   string
+	saveFormat string
 )
 
 var saveCmd = &cobra.Command{
 	Use:   "save",
 	Short: "Save timecard entries",
-	Long: `Save timecard entries for a week from a JSON file or stdin.
+	Long: `Save timecard entries for a week from a file or stdin.
+
+The input format is picked from the file extension (.json, .yaml/.yml,
+.toml, .csv, .tsv) or set with --format; stdin defaults to JSON.
 
 The JSON format should be:
 {
@@ -42,22 +47,46 @@ The JSON format should be:
   ]
 }
 
-Use "-" as the file argument to read from stdin.`,
+YAML and TOML documents use the same field names.
+
+CSV and TSV input has one row per day with the columns
+  date,project,activity,hours,note
+where date is YYYY-MM-DD, project is the project ID and activity the
+activity ID. A header row is optional. Rows are grouped into weeks by
+their date, so --date is not needed and one file may span several weeks.
+
+JSON, YAML and TOML entries may also key days by date instead of the
+days array, which likewise spreads them over the weeks they fall in:
+  {"project_id": "12345", "activity_id": "5",
+   "dates": {"2025-01-13": 8, "2025-01-20": {"hours": 4, "note": "..."}}}
+
+Days given by date (the dates map, or CSV/TSV rows) are laid over what
+the week already holds in TCRS: other days and rows are kept. A week
+given only as days arrays is replaced as a whole.
+
+When the input covers several weeks, every week is fetched and checked
+(locked, misaligned, too many rows, invalid hours) before any is
+written, then each week is saved in turn. A week failing on the server
+does not stop the rest; a per-week summary is printed and the exit
+status is non-zero if any week failed.
+
+Use "-" as the file argument to read from stdin.
+
+Entries with the same project and activity are merged into one row
+(hours added up, notes joined). Input that still needs more rows than
+the week form has is refused. Weeks that are submitted, approved or
+otherwise locked are refused.`,
 	Run: runSave,
 }
 
 func init() {
 	rootCmd.AddCommand(saveCmd)
-	saveCmd.Flags().StringVar(&saveDate, "date", "", "week start date in YYYY-MM-DD format (default: this week's Monday)")
-	saveCmd.Flags().StringVarP(&saveFile, "file", "f", "", "JSON file with entries (use '-' for stdin)")
+	saveCmd.Flags().StringVar(&saveDate, "date", "", "any date in the week: YYYY-MM-DD, 2025-W03, last-week, -2w, ... (default: this week)")
+	saveCmd.Flags().StringVarP(&saveFile, "file", "f", "", "file with entries (use '-' for stdin)")
+	saveCmd.Flags().StringVar(&saveFormat, "format", "", "input format: json, yaml, toml, csv or tsv (default: from file extension)")
 	saveCmd.MarkFlagRequired("file")
 }
 
-// SaveInput represents the input JSON structure.
-type SaveInput struct {
-	Entries []client.SaveEntry `json:"entries"`
-}
-
 func runSave(cmd *cobra.Command, args []string) {
 	userID := findLoggedInUser()
 	if userID == "" {
@@ -76,20 +105,10 @@ func runSave(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Calculate week start date (Monday) if not specified
-	date := saveDate
-	if date == "" {
-		now := time.Now()
-		weekday := int(now.Weekday())
-		if weekday == 0 {
-			weekday = 7 // Sunday
-		}
-		monday := now.AddDate(0, 0, -(weekday - 1))
-		date = monday.Format("2006-01-02")
-	}
+	// Normalize the date to the start of its week (default: this week)
+	date := resolveWeek(saveDate)
 
 	// Read input
-	var input SaveInput
 	var reader io.Reader
 
 	if saveFile == "-" {
@@ -116,36 +135,187 @@ func runSave(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	if err := json.Unmarshal(data, &input); err != nil {
-		printError("Failed to parse JSON", err)
+	format := saveFormat
+	if format == "" {
+		format = input.FormatFromPath(saveFile)
+	}
+
+	weeks, err := input.Decode(data, format, date, cfg.WeekStart)
+	if err != nil {
+		printError("Failed to parse input", err)
 		os.Exit(1)
 	}
 
-	if len(input.Entries) == 0 {
+	totalEntries := 0
+	for _, week := range weeks {
+		totalEntries += len(week.Entries)
+	}
+	if totalEntries == 0 {
 		printError("No entries to save", fmt.Errorf("entries array is empty"))
 		os.Exit(1)
 	}
 
+	// Drop weeks without entries and check every week before writing any
+	pending := make([]input.Week, 0, len(weeks))
+	for _, week := range weeks {
+		if len(week.Entries) > 0 {
+			pending = append(pending, week)
+		}
+	}
+	pending, _ = prepareWeeks(c, pending)
+
+	if len(pending) == 1 {
+		saveSingleWeek(c, pending[0])
+		return
+	}
+	saveWeeks(c, pending)
+}
+
+// prepareWeeks fetches every week and checks it before any is written,
+// so a locked, misaligned or overfull week does not leave the weeks
+// before it saved. Dated weeks are laid over the entries already saved
+// for them; other weeks replace them. It returns the weeks to save with
+// how many saved rows were kept per week, and exits if any week fails.
+func prepareWeeks(c *client.Client, weeks []input.Week) ([]input.Week, map[string]int) {
+	prepared := make([]input.Week, 0, len(weeks))
+	kept := make(map[string]int, len(weeks))
+	for _, week := range weeks {
+		if IsVerbose() {
+			fmt.Fprintf(os.Stderr, "Fetching week timecard for %s...\n", week.StartDate)
+		}
+		existing, err := c.GetWeekTimecard(week.StartDate)
+		if err != nil {
+			printError("Failed to get week timecard", fmt.Errorf("week starting %s: %w", week.StartDate, err))
+			os.Exit(1)
+		}
+
+		updates := client.CompactEntries(week.Entries)
+		entries := updates
+		if week.Dated {
+			entries = client.OverlayEntries(existing.SaveEntries(), updates)
+		}
+		if err := existing.CheckSave(entries); errors.Is(err, client.ErrWeekLocked) {
+			printError("Cannot save timecard", fmt.Errorf("week starting %s is read-only: %w", week.StartDate, err))
+			os.Exit(1)
+		} else if err != nil {
+			printError("Cannot save timecard", fmt.Errorf("week starting %s: %w", week.StartDate, err))
+			os.Exit(1)
+		}
+		if err := client.ValidateEntries(week.StartDate, entries, cfg.MaxDailyHours, cfg.HoursStep); err != nil {
+			printError("Invalid entries", fmt.Errorf("week starting %s: %w", week.StartDate, err))
+			os.Exit(1)
+		}
+		kept[week.StartDate] = len(entries) - len(updates)
+		prepared = append(prepared, input.Week{StartDate: week.StartDate, Entries: entries})
+	}
+	return prepared, kept
+}
+
+// saveSingleWeek saves one week and exits on failure.
+func saveSingleWeek(c *client.Client, week input.Week) {
+	week.Entries = client.CompactEntries(week.Entries)
 	if IsVerbose() {
-		fmt.Printf("Saving %d entries for week starting %s...\n", len(input.Entries), date)
+		fmt.Printf("Saving %d entries for week starting %s...\n", len(week.Entries), week.StartDate)
 	}
 
-	err = c.SaveWeekTimecard(date, input.Entries)
+	err := c.SaveWeekTimecard(week.StartDate, week.Entries)
+	if errors.Is(err, client.ErrWeekLocked) {
+		printError("Cannot save timecard", fmt.Errorf("week starting %s is read-only: %w", week.StartDate, err))
+		os.Exit(1)
+	}
 	if err != nil {
-		printError("Failed to save timecard", err)
+		printSaveError("Failed to save timecard", err)
 		os.Exit(1)
 	}
 
 	if IsJSON() {
 		result := map[string]interface{}{
 			"success":         true,
-			"week_start_date": date,
-			"entries_saved":   len(input.Entries),
+			"week_start_date": week.StartDate,
+			"entries_saved":   len(week.Entries),
 			"message":         "Timecard saved successfully",
 		}
 		data, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(data))
 	} else {
-		fmt.Printf("Successfully saved %d entries for week starting %s\n", len(input.Entries), date)
+		fmt.Printf("Successfully saved %d entries for week starting %s\n", len(week.Entries), week.StartDate)
+	}
+}
+
+// saveWeeks saves several weeks one after another. A failing week does
+// not stop the others; a summary of every week is printed and the command
+// exits non-zero if any week failed.
+func saveWeeks(c *client.Client, weeks []input.Week) {
+	results := make([]map[string]interface{}, 0, len(weeks))
+	failed := 0
+
+	for _, week := range weeks {
+		week.Entries = client.CompactEntries(week.Entries)
+		if IsVerbose() {
+			fmt.Printf("Saving %d entries for week starting %s...\n", len(week.Entries), week.StartDate)
+		}
+
+		result := map[string]interface{}{
+			"week_start_date": week.StartDate,
+			"success":         true,
+			"entries_saved":   len(week.Entries),
+		}
+		if err := c.SaveWeekTimecard(week.StartDate, week.Entries); err != nil {
+			failed++
+			result["success"] = false
+			result["entries_saved"] = 0
+			result["error"] = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	if IsJSON() {
+		summary := map[string]interface{}{
+			"success": failed == 0,
+			"saved":   len(weeks) - failed,
+			"failed":  failed,
+			"weeks":   results,
+		}
+		data, _ := json.MarshalIndent(summary, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		for _, r := range results {
+			if r["success"] == true {
+				fmt.Printf("  %s  saved %d entries\n", r["week_start_date"], r["entries_saved"])
+			} else {
+				fmt.Printf("  %s  FAILED: %s\n", r["week_start_date"], r["error"])
+			}
+		}
+		fmt.Printf("\nSaved %d of %d weeks\n", len(weeks)-failed, len(weeks))
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// printSaveError prints a save or submit failure. In JSON mode the
+// server's messages and the affected row and day are included.
+func printSaveError(msg string, err error) {
+	var saveErr *client.SaveError
+	if !IsJSON() || !errors.As(err, &saveErr) {
+		printError(msg, err)
+		return
+	}
+
+	result := map[string]interface{}{
+		"success":         false,
+		"error":           err.Error(),
+		"message":         msg,
+		"server_messages": saveErr.Messages,
+	}
+	if saveErr.Row >= 0 {
+		result["row"] = saveErr.Row
+		result["project_id"] = saveErr.ProjectID
+	}
+	if saveErr.Day >= 0 {
+		result["day"] = saveErr.Day
 	}
+	data, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(data))
 }
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+)
+
+var (
+	submitDate  string
+	submitHours float64
+	submitForce bool
+)
+
+var submitCmd = &cobra.Command{
+	Use:   "submit",
+	Short: "Submit a week timecard for approval",
+	Long: `Send the saved entries of a week for approval.
+
+Before submitting, every working day is checked to have at least the
+required hours (--hours, default TCRS_EXPECTED_HOURS or 8). Working days are Monday to Friday, less the days off and
+plus the make-up workdays of the calendar (see "tcrs holidays"). Use
+--force to submit anyway.
+
+Submitted weeks are locked and can no longer be saved. After posting,
+the week is fetched again to confirm it shows as submitted.`,
+	Run: runSubmit,
+}
+
+func init() {
+	rootCmd.AddCommand(submitCmd)
+	submitCmd.Flags().StringVar(&submitDate, "date", "", "any date in the week: YYYY-MM-DD, 2025-W03, last-week, -2w, ... (default: this week)")
+	submitCmd.Flags().Float64Var(&submitHours, "hours", 0, "required hours per working day (default: TCRS_EXPECTED_HOURS or 8)")
+	submitCmd.Flags().BoolVar(&submitForce, "force", false, "submit even if some working days are short of the required hours")
+}
+
+func runSubmit(cmd *cobra.Command, args []string) {
+	userID := findLoggedInUser()
+	if userID == "" {
+		printError("Not logged in", fmt.Errorf("please login first with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	c, err := client.NewClient(userID, cfg)
+	if err != nil {
+		printError("Failed to create client", err)
+		os.Exit(1)
+	}
+
+	if !c.IsLoggedIn() {
+		printError("Session expired", fmt.Errorf("please login again with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	// Normalize the date to the start of its week (default: this week)
+	date := resolveWeek(submitDate)
+
+	if IsVerbose() {
+		fmt.Printf("Checking week timecard for %s...\n", date)
+	}
+
+	week, err := c.GetWeekTimecard(date)
+	if err != nil {
+		printError("Failed to get week timecard", err)
+		os.Exit(1)
+	}
+
+	required := cfg.ExpectedHours
+	if submitHours > 0 {
+		required = submitHours
+	}
+
+	if short := week.ShortDays(required, loadCalendar().IsWorkday); len(short) > 0 && !submitForce {
+		missing := make([]string, 0, len(short))
+		for _, dayIdx := range short {
+			d, _ := week.ColumnDate(dayIdx)
+			total := 0.0
+			if dayIdx < len(week.DailyTotals) {
+				total = week.DailyTotals[dayIdx]
+			}
+			missing = append(missing, fmt.Sprintf("%s %s %.1fh", dates.WeekdayLabel(cfg.Locale, d.Weekday()), d.Format("01/02"), total))
+		}
+		printError("Week is incomplete",
+			fmt.Errorf("below %.1f hours on %s (use --force to submit anyway)", required, strings.Join(missing, ", ")))
+		os.Exit(1)
+	}
+
+	if IsVerbose() {
+		fmt.Printf("Submitting week starting %s...\n", date)
+	}
+
+	err = c.SubmitWeekTimecard(date)
+	if errors.Is(err, client.ErrWeekLocked) {
+		printError("Cannot submit timecard", fmt.Errorf("week starting %s is read-only: %w", date, err))
+		os.Exit(1)
+	}
+	if errors.Is(err, client.ErrNoSubmitButton) {
+		printError("Cannot submit timecard", fmt.Errorf("%w; submit the week in the TCRS web page instead", err))
+		os.Exit(1)
+	}
+	if err != nil {
+		printSaveError("Failed to submit timecard", err)
+		os.Exit(1)
+	}
+
+	if IsJSON() {
+		result := map[string]interface{}{
+			"success":         true,
+			"week_start_date": date,
+			"message":         "Timecard submitted successfully",
+		}
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("Successfully submitted week starting %s\n", date)
+	}
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/input"
+	"github.com/user/tcrs/internal/suggest"
+	"github.com/user/tcrs/internal/tracker"
+)
+
+var (
+	suggestRepos   []string
+	suggestICS     []string
+	suggestWeek    string
+	suggestAuthor  string
+	suggestMapping string
+	suggestHours   float64
+	suggestOutput  string
+	suggestMerge   bool
+)
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Suggest a week's entries from your git commits and calendar",
+	Long: `Suggest a week's timecard from the commits you made in git repositories
+and the meetings in your calendar.
+
+Commits by your author email (--author, TCRS_GIT_EMAIL or each
+repository's user.email) are grouped by day. Repositories are mapped to
+TCRS projects and activities by the "source: git" rules of the mapping
+file (--mapping, default ~/.tcrs/mapping.yaml), matching the repository's
+directory name:
+
+  rules:
+    - source: git
+      match: billing-service
+      project: "12345"
+      activity: Development
+
+Meetings are read from iCalendar files exported from Outlook or Google
+Calendar (--from-ics) and mapped by the "source: ics" rules. Rules with
+contains match the meeting title, rules with match its categories:
+
+  rules:
+    - source: ics
+      contains: Sprint Review
+      project: "12345"
+      activity: Meeting
+
+Each meeting's time is booked as it is in the calendar, summed per day
+and rounded to TCRS_HOURS_STEP, with the meeting titles as the note.
+Meetings no rule matches, all-day events and events marked free are
+left out.
+
+The hours of a working day not spent in meetings are shared between the
+day's projects by number of commits, and commit subjects become the
+note. Days off in the holiday calendar get no hours from commits.
+
+The result is JSON in the format of "tcrs save"; with --merge it is laid
+over what the week already holds in TCRS. Edit it, then save it:
+
+  tcrs suggest --from-git ~/src/api --from-ics ~/calendar.ics --week last-week --merge -o week.json
+  tcrs save --date last-week --file week.json`,
+	Run: runSuggest,
+}
+
+func init() {
+	rootCmd.AddCommand(suggestCmd)
+	suggestCmd.Flags().StringArrayVar(&suggestRepos, "from-git", nil, "git repository to read commits from (repeatable)")
+	suggestCmd.Flags().StringArrayVar(&suggestICS, "from-ics", nil, "iCalendar file to read meetings from (repeatable)")
+	suggestCmd.Flags().StringVar(&suggestWeek, "week", "", "any date in the week: YYYY-MM-DD, 2025-W03, last-week, -2w, ... (default: this week)")
+	suggestCmd.Flags().StringVar(&suggestAuthor, "author", "", "author email of your commits (default: TCRS_GIT_EMAIL or git user.email)")
+	suggestCmd.Flags().StringVar(&suggestMapping, "mapping", "", "rules mapping repositories to TCRS (default: ~/.tcrs/mapping.yaml)")
+	suggestCmd.Flags().Float64Var(&suggestHours, "hours", 0, "hours per working day (default: TCRS_EXPECTED_HOURS or 8)")
+	suggestCmd.Flags().StringVarP(&suggestOutput, "output", "o", "-", "output file, or - for stdout")
+	suggestCmd.Flags().BoolVar(&suggestMerge, "merge", false, "lay the suggestion over the entries already saved for the week")
+}
+
+func runSuggest(cmd *cobra.Command, args []string) {
+	repos := append(append([]string{}, suggestRepos...), args...)
+	if len(repos) == 0 && len(suggestICS) == 0 {
+		printError("Nothing to suggest from", fmt.Errorf("name a repository with --from-git <repo> or a calendar with --from-ics <file>"))
+		os.Exit(1)
+	}
+
+	week := resolveWeek(suggestWeek)
+	from, _ := time.Parse(dates.Layout, week)
+	to := from.AddDate(0, 0, 6)
+
+	path := suggestMapping
+	if path == "" {
+		path = cfg.MappingFile()
+	}
+	mapping, err := tracker.LoadMapping(path)
+	if err != nil {
+		printError("Failed to load mapping", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var commits []suggest.Commit
+	for _, repo := range repos {
+		author := suggestAuthor
+		if author == "" {
+			author = cfg.GitEmail
+		}
+		if author == "" {
+			if author, err = suggest.GitAuthor(ctx, repo); err != nil || author == "" {
+				printError("Unknown author", fmt.Errorf("%s has no user.email; use --author or TCRS_GIT_EMAIL", repo))
+				os.Exit(1)
+			}
+		}
+		found, err := suggest.GitCommits(ctx, repo, author, from, to)
+		if err != nil {
+			printError("Failed to read commits", err)
+			os.Exit(1)
+		}
+		if IsVerbose() {
+			fmt.Fprintf(os.Stderr, "%s: %d commits by %s\n", repo, len(found), author)
+		}
+		commits = append(commits, found...)
+	}
+
+	var meetings []suggest.Meeting
+	for _, file := range suggestICS {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			printError("Failed to read calendar", err)
+			os.Exit(1)
+		}
+		found, err := suggest.ReadICS(data, from, to)
+		if err != nil {
+			printError("Failed to parse calendar", fmt.Errorf("%s: %w", file, err))
+			os.Exit(1)
+		}
+		if IsVerbose() {
+			fmt.Fprintf(os.Stderr, "%s: %d meetings\n", file, len(found))
+		}
+		meetings = append(meetings, found...)
+	}
+	rows, unmatched, err := suggest.FromMeetings(meetings, mapping, tracker.Rounding{Step: cfg.HoursStep})
+	if err != nil {
+		printError("Failed to map meetings", err)
+		os.Exit(1)
+	}
+	if len(unmatched) > 0 && !IsJSON() {
+		fmt.Fprintf(os.Stderr, "%d meetings match no mapping rule and were left out\n", len(unmatched))
+		if IsVerbose() {
+			for _, m := range unmatched {
+				fmt.Fprintf(os.Stderr, "  %s %s\n", m.Start.Format("2006-01-02 15:04"), m.Summary)
+			}
+		}
+	}
+
+	// Commits share the hours of each working day left after meetings
+	meetingHours := make(map[string]float64)
+	for _, row := range rows {
+		meetingHours[row.Date.Format(dates.Layout)] += row.Hours.Float()
+	}
+	hours := cfg.ExpectedHours
+	if suggestHours > 0 {
+		hours = suggestHours
+	}
+	cal := loadCalendar()
+	hoursOn := func(d time.Time) float64 {
+		if !cal.IsWorkday(d) {
+			return 0
+		}
+		return math.Max(0, hours-meetingHours[d.Format(dates.Layout)])
+	}
+	commitRows, err := suggest.FromCommits(commits, mapping, hoursOn, cfg.HoursStep)
+	if err != nil {
+		printError("Failed to map commits", err)
+		os.Exit(1)
+	}
+	rows = append(rows, commitRows...)
+
+	// Project and activity names in the mapping are resolved to IDs
+	userID := findLoggedInUser()
+	if userID == "" {
+		printError("Not logged in", fmt.Errorf("please login first with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	c, err := client.NewClient(userID, cfg)
+	if err != nil {
+		printError("Failed to create client", err)
+		os.Exit(1)
+	}
+
+	if !c.IsLoggedIn() {
+		printError("Session expired", fmt.Errorf("please login again with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	weeks, err := input.ResolveSheet(rows, c.GetProjectsAndActivities, cfg.WeekStart)
+	if err != nil {
+		printError("Failed to resolve projects", err)
+		os.Exit(1)
+	}
+	result := input.Week{StartDate: week, Entries: []client.SaveEntry{}}
+	if len(weeks) > 0 {
+		result = weeks[0]
+	}
+	if suggestMerge {
+		if IsVerbose() {
+			fmt.Fprintf(os.Stderr, "Fetching week timecard for %s...\n", week)
+		}
+		existing, err := c.GetWeekTimecard(week)
+		if err != nil {
+			printError("Failed to get week timecard", err)
+			os.Exit(1)
+		}
+		result.Entries = client.OverlayEntries(existing.SaveEntries(), result.Entries)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	if suggestOutput == "-" {
+		fmt.Println(string(data))
+	} else if err := os.WriteFile(suggestOutput, append(data, '\n'), 0644); err != nil {
+		printError("Failed to write suggestion", err)
+		os.Exit(1)
+	}
+
+	if !IsJSON() {
+		fmt.Fprintf(os.Stderr, "Suggested %d entries from %d commits and %d meetings. Review, then save with:\n  tcrs save --date %s --file <file>\n",
+			len(result.Entries), len(commits), len(meetings)-len(unmatched), week)
+	}
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/input"
+	"github.com/user/tcrs/internal/timer"
+	"github.com/user/tcrs/internal/tracker"
+)
+
+var (
+	timerNote   string
+	timerWeek   string
+	timerYes    bool
+	timerDryRun bool
+)
+
+var timerCmd = &cobra.Command{
+	Use:   "timer",
+	Short: "Track time with a start/stop timer",
+	Long: `Track time with a local start/stop timer and push it to TCRS.
+
+Intervals are kept in ~/.tcrs/timer.json; starting a timer
+stops the one running. Projects and activities are IDs or names and are
+looked up when the week is pushed.
+
+  tcrs timer start "Project Alpha" Development --note "API review"
+  tcrs timer stop
+  tcrs timer push --week this-week`,
+}
+
+var timerStartCmd = &cobra.Command{
+	Use:   "start <project> [activity]",
+	Short: "Start a timer for a project and activity",
+	Args:  cobra.RangeArgs(1, 2),
+	Run:   runTimerStart,
+}
+
+var timerStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Args:  cobra.NoArgs,
+	Run:   runTimerStop,
+}
+
+var timerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the running timer and today's tracked time",
+	Args:  cobra.NoArgs,
+	Run:   runTimerStatus,
+}
+
+var timerPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Save a week's tracked time to TCRS",
+	Long: `Save the time tracked in a week to TCRS.
+
+Completed intervals are summed per day, project and activity and rounded
+to TCRS_HOURS_STEP; notes are joined. The sums replace the same
+project/activity's days in the week, everything else is kept, so pushing
+again after tracking more time updates the same days. A running timer
+is not pushed until it is stopped.`,
+	Args: cobra.NoArgs,
+	Run:  runTimerPush,
+}
+
+func init() {
+	rootCmd.AddCommand(timerCmd)
+	timerCmd.AddCommand(timerStartCmd, timerStopCmd, timerStatusCmd, timerPushCmd)
+
+	timerStartCmd.Flags().StringVar(&timerNote, "note", "", "note for the tracked time")
+	timerPushCmd.Flags().StringVar(&timerWeek, "week", "", "any date in the week: YYYY-MM-DD, 2025-W03, last-week, -2w, ... (default: this week)")
+	timerPushCmd.Flags().BoolVarP(&timerYes, "yes", "y", false, "save without asking for confirmation")
+	timerPushCmd.Flags().BoolVar(&timerDryRun, "dry-run", false, "show the preview without saving")
+}
+
+// loadTimer loads the timer store and exits with an error if it cannot
+// be read.
+func loadTimer() *timer.Store {
+	store, err := timer.Load(cfg.TimerFile())
+	if err != nil {
+		printError("Failed to load timer", err)
+		os.Exit(1)
+	}
+	return store
+}
+
+// saveTimer writes the timer store and exits with an error on failure.
+func saveTimer(store *timer.Store) {
+	if err := store.Save(cfg.TimerFile()); err != nil {
+		printError("Failed to save timer", err)
+		os.Exit(1)
+	}
+}
+
+func runTimerStart(cmd *cobra.Command, args []string) {
+	activity := ""
+	if len(args) > 1 {
+		activity = args[1]
+	}
+
+	now := time.Now()
+	store := loadTimer()
+	stopped := store.Start(args[0], activity, timerNote, now)
+	saveTimer(store)
+
+	if IsJSON() {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"success": true,
+			"running": store.Running,
+			"stopped": stopped,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if stopped != nil {
+		fmt.Printf("Stopped %s after %s\n", intervalLabel(*stopped), formatDuration(stopped.Duration(now)))
+	}
+	fmt.Printf("Started %s at %s\n", intervalLabel(*store.Running), now.Format("15:04"))
+}
+
+func runTimerStop(cmd *cobra.Command, args []string) {
+	now := time.Now()
+	store := loadTimer()
+	stopped, err := store.Stop(now)
+	if err != nil {
+		printError("Cannot stop timer", err)
+		os.Exit(1)
+	}
+	saveTimer(store)
+
+	if IsJSON() {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"success": true,
+			"stopped": stopped,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Printf("Stopped %s after %s\n", intervalLabel(*stopped), formatDuration(stopped.Duration(now)))
+}
+
+func runTimerStatus(cmd *cobra.Command, args []string) {
+	now := time.Now()
+	store := loadTimer()
+	today := store.Total(now, now, now)
+	weekStart := dates.WeekStartOn(now, cfg.WeekStart)
+	week := store.Total(weekStart, weekStart.AddDate(0, 0, 6), now)
+
+	if IsJSON() {
+		result := map[string]interface{}{
+			"running":     store.Running,
+			"today_hours": today.Hours(),
+			"week_hours":  week.Hours(),
+		}
+		if store.Running != nil {
+			result["elapsed_hours"] = store.Running.Duration(now).Hours()
+		}
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if store.Running == nil {
+		fmt.Println("No timer running")
+	} else {
+		fmt.Printf("Running: %s since %s (%s)\n", intervalLabel(*store.Running),
+			store.Running.Start.Local().Format("15:04"), formatDuration(store.Running.Duration(now)))
+	}
+	fmt.Printf("Today:   %s\n", formatDuration(today))
+	fmt.Printf("Week:    %s\n", formatDuration(week))
+}
+
+func runTimerPush(cmd *cobra.Command, args []string) {
+	if IsJSON() && !timerYes && !timerDryRun {
+		printError("Confirmation required", fmt.Errorf("use --yes or --dry-run together with --json"))
+		os.Exit(1)
+	}
+
+	week := resolveWeek(timerWeek)
+	from, _ := time.Parse(dates.Layout, week)
+	to := from.AddDate(0, 0, 6)
+
+	store := loadTimer()
+	rows := store.Rows(from, to, tracker.Rounding{Step: cfg.HoursStep})
+	if store.Running != nil && !IsJSON() {
+		fmt.Fprintf(os.Stderr, "Timer for %s is still running and is not pushed\n", intervalLabel(*store.Running))
+	}
+	if len(rows) == 0 {
+		printError("Nothing to push", fmt.Errorf("no tracked time in the week starting %s", week))
+		os.Exit(1)
+	}
+
+	userID := findLoggedInUser()
+	if userID == "" {
+		printError("Not logged in", fmt.Errorf("please login first with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	c, err := client.NewClient(userID, cfg)
+	if err != nil {
+		printError("Failed to create client", err)
+		os.Exit(1)
+	}
+
+	if !c.IsLoggedIn() {
+		printError("Session expired", fmt.Errorf("please login again with: tcrs login <user> <pass>"))
+		os.Exit(1)
+	}
+
+	weeks, err := input.ResolveSheet(rows, c.GetProjectsAndActivities, cfg.WeekStart)
+	if err != nil {
+		printError("Failed to resolve projects", err)
+		os.Exit(1)
+	}
+	merged, kept := prepareWeeks(c, weeks)
+
+	if IsJSON() && timerDryRun {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"success": true,
+			"dry_run": true,
+			"rows":    len(rows),
+			"weeks":   merged,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if !IsJSON() {
+		printImportPreview(rows, merged, kept)
+	}
+	if timerDryRun {
+		return
+	}
+	if !timerYes && !confirm(fmt.Sprintf("Save the week starting %s to TCRS?", week)) {
+		fmt.Println("Push cancelled")
+		return
+	}
+	saveSingleWeek(c, merged[0])
+}
+
+// intervalLabel names an interval's project, activity and note.
+func intervalLabel(iv timer.Interval) string {
+	label := iv.Project
+	if iv.Activity != "" {
+		label += " / " + iv.Activity
+	}
+	if iv.Note != "" {
+		label += fmt.Sprintf(" (%s)", iv.Note)
+	}
+	return label
+}
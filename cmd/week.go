@@ -3,11 +3,15 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
-	"time"
+	"strconv"
 
 	"github.com/spf13/cobra"
+	"github.com/user/tcrs/internal/calendar"
 	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/textwidth"
 )
 
 var weekDate string
@@ -21,7 +25,7 @@ var weekCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(weekCmd)
-	weekCmd.Flags().StringVar(&weekDate, "date", "", "week start date in YYYY-MM-DD format (default: this week's Monday)")
+	weekCmd.Flags().StringVar(&weekDate, "date", "", "any date in the week: YYYY-MM-DD, 2025-W03, last-week, -2w, ... (default: this week)")
 }
 
 func runWeek(cmd *cobra.Command, args []string) {
@@ -42,17 +46,8 @@ func runWeek(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Calculate week start date (Monday) if not specified
-	date := weekDate
-	if date == "" {
-		now := time.Now()
-		weekday := int(now.Weekday())
-		if weekday == 0 {
-			weekday = 7 // Sunday
-		}
-		monday := now.AddDate(0, 0, -(weekday - 1))
-		date = monday.Format("2006-01-02")
-	}
+	// Normalize the date to the start of its week (default: this week)
+	date := resolveWeek(weekDate)
 
 	if IsVerbose() {
 		fmt.Printf("Fetching week timecard for %s...\n", date)
@@ -73,28 +68,50 @@ func runWeek(cmd *cobra.Command, args []string) {
 }
 
 func printWeekTimecard(tc *client.WeekTimecard) {
-	// Parse week start date
-	startDate, err := time.Parse("2006-01-02", tc.WeekStartDate)
-	if err != nil {
-		startDate = time.Now()
-	}
-
 	// Print header
 	fmt.Printf("Week Timecard: %s\n", tc.WeekStartDate)
+	status := string(tc.Status)
+	if status == "" {
+		status = "unknown"
+	}
+	if tc.Locked {
+		status += " (locked)"
+	}
+	fmt.Printf("Status: %s\n", status)
+	for _, comment := range tc.ApproverComments {
+		fmt.Printf("Approver comment: %s\n", comment)
+	}
+	if tc.Misaligned() {
+		fmt.Printf("Warning: the server's week starts %s, not %s (check TCRS_WEEK_START)\n", tc.Dates[0], tc.WeekStartDate)
+	}
 	fmt.Println()
 
-	// Day headers
-	days := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
-	dates := make([]string, 7)
+	// Day headers, preferring the dates shown by the server
+	days := dates.WeekdayLabels(cfg.Locale, cfg.WeekStart)
+	columnDates := make([]string, 7)
+	marks := []string{" ", " ", " ", " ", " ", " ", " "}
+	var calendarDays []calendar.Day
+	cal := loadCalendar()
 	for i := 0; i < 7; i++ {
-		d := startDate.AddDate(0, 0, i)
-		dates[i] = d.Format("01/02")
+		d, ok := tc.ColumnDate(i)
+		if !ok {
+			continue
+		}
+		days[i] = dates.WeekdayLabel(cfg.Locale, d.Weekday())
+		columnDates[i] = d.Format("01/02")
+		if day, ok := cal.Lookup(d); ok {
+			calendarDays = append(calendarDays, day)
+			marks[i] = "*"
+			if !day.Kind.IsDayOff() {
+				marks[i] = "+"
+			}
+		}
 	}
 
 	// Print table header
 	fmt.Printf("%-30s", "Project/Activity")
 	for i, day := range days {
-		fmt.Printf(" %s(%s)", day, dates[i])
+		fmt.Print(textwidth.Pad(marks[i]+day+"("+columnDates[i]+")", 11))
 	}
 	fmt.Println()
 
@@ -109,28 +126,12 @@ func printWeekTimecard(tc *client.WeekTimecard) {
 		fmt.Println("No entries")
 	} else {
 		for _, entry := range tc.Entries {
-			// Truncate project name if too long
-			name := entry.ProjectName
-			if len(name) > 28 {
-				name = name[:25] + "..."
-			}
-			fmt.Printf("%-30s", name)
+			fmt.Print(textwidth.Pad(textwidth.Truncate(entry.ProjectName, 28, "..."), 30))
 
 			for _, day := range entry.Days {
 				hoursStr := "   -   "
-				switch v := day.Hours.(type) {
-				case float64:
-					if v > 0 {
-						hoursStr = fmt.Sprintf("%7.1f", v)
-					}
-				case int:
-					if v > 0 {
-						hoursStr = fmt.Sprintf("%7d", v)
-					}
-				case string:
-					if v != "" {
-						hoursStr = fmt.Sprintf("%7s", v)
-					}
+				if h := day.Hours.Float(); h > 0 {
+					hoursStr = fmt.Sprintf("%7s", weekHours(h))
 				}
 				fmt.Printf(" %s   ", hoursStr)
 			}
@@ -150,12 +151,29 @@ func printWeekTimecard(tc *client.WeekTimecard) {
 	for _, total := range tc.DailyTotals {
 		weekTotal += total
 		if total > 0 {
-			fmt.Printf(" %7.1f   ", total)
+			fmt.Printf(" %7s   ", weekHours(total))
 		} else {
 			fmt.Printf("    -      ")
 		}
 	}
 	fmt.Println()
 
-	fmt.Printf("\nWeek Total: %.1f hours\n", weekTotal)
+	fmt.Printf("\nWeek Total: %s hours\n", weekHours(weekTotal))
+
+	if len(calendarDays) > 0 {
+		fmt.Println()
+		for _, day := range calendarDays {
+			mark := "*"
+			if !day.Kind.IsDayOff() {
+				mark = "+"
+			}
+			fmt.Printf("%s %s %s %s\n", mark, day.Date, day.Kind, day.Name)
+		}
+	}
+}
+
+// weekHours formats hours for the week table in full, so a quarter hour
+// is not shown rounded, but without the float noise of summed totals.
+func weekHours(h float64) string {
+	return strconv.FormatFloat(math.Round(h*100)/100, 'f', -1, 64)
 }
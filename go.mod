@@ -3,13 +3,18 @@ module github.com/user/tcrs
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/PuerkitoBio/goquery v1.8.1
 	github.com/spf13/cobra v1.8.0
+	golang.org/x/net v0.7.0
+	golang.org/x/term v0.14.0
+	golang.org/x/text v0.7.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/andybalholm/cascadia v1.3.1 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/net v0.7.0 // indirect
+	golang.org/x/sys v0.14.0 // indirect
 )
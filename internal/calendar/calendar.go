@@ -0,0 +1,181 @@
+// Package calendar stores public holidays, company days off, personal
+// leave and make-up workdays, and decides which days are working days.
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/dates"
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is what a calendar day is.
+type Kind string
+
+// Kinds of calendar days. Workday marks a make-up working day falling on
+// a weekend; all other kinds are days off.
+const (
+	KindHoliday Kind = "holiday"
+	KindCompany Kind = "company"
+	KindLeave   Kind = "leave"
+	KindWorkday Kind = "workday"
+)
+
+// ParseKind parses a kind name.
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(strings.ToLower(strings.TrimSpace(s))); k {
+	case KindHoliday, KindCompany, KindLeave, KindWorkday:
+		return k, nil
+	case "":
+		return KindHoliday, nil
+	default:
+		return "", fmt.Errorf("unknown kind %q (use holiday, company, leave or workday)", s)
+	}
+}
+
+// IsDayOff reports whether days of this kind are not worked.
+func (k Kind) IsDayOff() bool {
+	return k != KindWorkday
+}
+
+// Day is a single calendar day.
+type Day struct {
+	Date string `yaml:"date" json:"date"` // YYYY-MM-DD
+	Kind Kind   `yaml:"kind" json:"kind"`
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+}
+
+// Calendar is a set of days, at most one per date, kept in date order.
+type Calendar struct {
+	Days []Day `yaml:"days" json:"days"`
+}
+
+// Load reads a calendar file. A missing file is an empty calendar.
+func Load(path string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Calendar{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := ParseYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cal := &Calendar{}
+	cal.Add(days...)
+	return cal, nil
+}
+
+// Save writes the calendar to path as YAML.
+func (c *Calendar) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Add adds days to the calendar, replacing any day with the same date.
+func (c *Calendar) Add(days ...Day) {
+	byDate := make(map[string]int, len(c.Days))
+	for i, d := range c.Days {
+		byDate[d.Date] = i
+	}
+	for _, d := range days {
+		if i, ok := byDate[d.Date]; ok {
+			c.Days[i] = d
+			continue
+		}
+		byDate[d.Date] = len(c.Days)
+		c.Days = append(c.Days, d)
+	}
+	sort.SliceStable(c.Days, func(i, j int) bool {
+		return c.Days[i].Date < c.Days[j].Date
+	})
+}
+
+// Remove removes the day with the given date and reports whether there
+// was one.
+func (c *Calendar) Remove(date string) bool {
+	for i, d := range c.Days {
+		if d.Date == date {
+			c.Days = append(c.Days[:i], c.Days[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the calendar day for t, if there is one.
+func (c *Calendar) Lookup(t time.Time) (Day, bool) {
+	date := t.Format(dates.Layout)
+	i := sort.Search(len(c.Days), func(i int) bool { return c.Days[i].Date >= date })
+	if i < len(c.Days) && c.Days[i].Date == date {
+		return c.Days[i], true
+	}
+	return Day{}, false
+}
+
+// IsWorkday reports whether t is a working day: Monday to Friday unless
+// it is a day off, or a weekend day marked as a make-up workday.
+func (c *Calendar) IsWorkday(t time.Time) bool {
+	if d, ok := c.Lookup(t); ok {
+		return !d.Kind.IsDayOff()
+	}
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// Between returns the days from from to to (inclusive).
+func (c *Calendar) Between(from, to time.Time) []Day {
+	lo, hi := from.Format(dates.Layout), to.Format(dates.Layout)
+	days := make([]Day, 0)
+	for _, d := range c.Days {
+		if d.Date >= lo && d.Date <= hi {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// ParseYAML parses a calendar YAML document, either a list of days or a
+// mapping with a "days" list. Days without a kind are holidays.
+func ParseYAML(data []byte) ([]Day, error) {
+	var days []Day
+	if err := yaml.Unmarshal(data, &days); err != nil {
+		var cal Calendar
+		if err := yaml.Unmarshal(data, &cal); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		days = cal.Days
+	}
+	return normalize(days)
+}
+
+// normalize checks the dates and kinds of days and fills in defaults.
+func normalize(days []Day) ([]Day, error) {
+	for i, d := range days {
+		t, err := dates.Parse(d.Date, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("day %d: %w", i+1, err)
+		}
+		kind, err := ParseKind(string(d.Kind))
+		if err != nil {
+			return nil, fmt.Errorf("day %d (%s): %w", i+1, d.Date, err)
+		}
+		days[i].Date = t.Format(dates.Layout)
+		days[i].Kind = kind
+	}
+	return days, nil
+}
@@ -0,0 +1,69 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/ics"
+)
+
+// makeUpWords mark events that are make-up workdays rather than days off,
+// as in Taiwan's government calendar.
+var makeUpWords = []string{"補班", "補行上班", "make-up work", "makeup work"}
+
+// ParseICS parses the events of an iCalendar file into days of the given
+// kind. Events spanning several days yield one day each. Events whose
+// summary names a make-up workday (補班) become workdays.
+func ParseICS(data []byte, kind Kind) ([]Day, error) {
+	var days []Day
+	for _, event := range ics.Events(data) {
+		eventDays, err := icsEventDays(event, kind)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, eventDays...)
+	}
+	return days, nil
+}
+
+// icsEventDays returns the days covered by an event.
+func icsEventDays(event ics.Event, kind Kind) ([]Day, error) {
+	summary := event.Summary()
+	start, err := parseICSDate(event.Value("DTSTART"))
+	if err != nil {
+		return nil, fmt.Errorf("event %q: DTSTART: %w", summary, err)
+	}
+	end := start.AddDate(0, 0, 1) // DTEND is exclusive
+	if p, ok := event.Get("DTEND"); ok {
+		if end, err = parseICSDate(p.Value); err != nil {
+			return nil, fmt.Errorf("event %q: DTEND: %w", summary, err)
+		}
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+	}
+
+	lower := strings.ToLower(summary)
+	for _, word := range makeUpWords {
+		if strings.Contains(lower, word) {
+			kind = KindWorkday
+			break
+		}
+	}
+
+	var days []Day
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, Day{Date: d.Format(dates.Layout), Kind: kind, Name: summary})
+	}
+	return days, nil
+}
+
+// parseICSDate parses a DATE or DATE-TIME value, ignoring the time of day.
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return time.Parse("20060102", value[:8])
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// readBody reads a response body and transcodes it to UTF-8. The charset
+// is taken from the Content-Type header, a BOM or the page's <meta> tags.
+// Undeclared pages are treated as UTF-8. The detected encoding is
+// remembered so that later form posts can be encoded the same way.
+func (c *Client) readBody(resp *http.Response) (string, error) {
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	enc, name, certain := charset.DetermineEncoding(raw, resp.Header.Get("Content-Type"))
+	// DetermineEncoding falls back to windows-1252 when nothing is declared;
+	// TCRS pages without a declaration are plain ASCII or UTF-8.
+	if !certain && name == "windows-1252" && utf8.Valid(raw) {
+		enc, name = encoding.Nop, "utf-8"
+	}
+	if enc == nil || enc == encoding.Nop || name == "utf-8" {
+		c.setEncoding(unicode.UTF8)
+		return string(raw), nil
+	}
+	c.setEncoding(enc)
+
+	decoded, err := enc.NewDecoder().Bytes(raw)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// setEncoding remembers the server charset. Pages may be read by several
+// goroutines at once, so access is guarded by encodingMu.
+func (c *Client) setEncoding(enc encoding.Encoding) {
+	c.encodingMu.Lock()
+	defer c.encodingMu.Unlock()
+	c.encoding = enc
+}
+
+// getEncoding returns the server charset remembered by readBody.
+func (c *Client) getEncoding() encoding.Encoding {
+	c.encodingMu.Lock()
+	defer c.encodingMu.Unlock()
+	return c.encoding
+}
+
+// formEscape encodes a form value in the server's charset and escapes it
+// for an application/x-www-form-urlencoded body. Characters the charset
+// cannot represent are replaced rather than failing the whole post.
+func (c *Client) formEscape(value string) string {
+	enc := c.getEncoding()
+	if enc == nil || enc == unicode.UTF8 || value == "" {
+		return url.QueryEscape(value)
+	}
+
+	encoded, err := encoding.ReplaceUnsupported(enc.NewEncoder()).String(value)
+	if err != nil {
+		return url.QueryEscape(value)
+	}
+	return url.QueryEscape(encoded)
+}
+
+// encodeForm encodes url.Values like Values.Encode, using formEscape for
+// keys and values.
+func (c *Client) encodeForm(values url.Values) string {
+	parts := make([]string, 0, len(values))
+	for key, vals := range values {
+		for _, v := range vals {
+			parts = append(parts, c.formEscape(key)+"="+c.formEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
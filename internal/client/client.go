@@ -1,16 +1,18 @@
 package client
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/user/tcrs/internal/config"
+	"golang.org/x/text/encoding"
 )
 
 // Client is the TCRS HTTP client.
@@ -20,6 +22,9 @@ type Client struct {
 	sessionManager *SessionManager
 	userID         string
 	loggedIn       bool
+	encoding       encoding.Encoding // server charset, detected from responses
+	encodingMu     sync.Mutex
+	throttle       *throttle
 }
 
 // NewClient creates a new TCRS client.
@@ -45,6 +50,7 @@ func NewClient(userID string, cfg *config.Config) (*Client, error) {
 		sessionManager: sm,
 		userID:         userID,
 		loggedIn:       sm.HasValidSession(),
+		throttle:       newThrottle(cfg.RequestInterval),
 	}
 
 	return c, nil
@@ -71,10 +77,16 @@ func (c *Client) Login(password string) error {
 	}
 	c.setCommonHeaders(req)
 
-	_, err = c.httpClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to get login page: %w", err)
 	}
+	// Read the page so the server charset is known before posting
+	_, err = c.readBody(resp)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
 
 	// Perform login
 	loginURL := c.cfg.BaseURL + "/servlet/VerifController"
@@ -84,7 +96,7 @@ func (c *Client) Login(password string) error {
 		"pw":     {password},
 	}
 
-	req, err = http.NewRequest("POST", loginURL, strings.NewReader(data.Encode()))
+	req, err = http.NewRequest("POST", loginURL, strings.NewReader(c.encodeForm(data)))
 	if err != nil {
 		return err
 	}
@@ -92,17 +104,16 @@ func (c *Client) Login(password string) error {
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Referer", loginPageURL)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err = c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("login request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	bodyStr, err := c.readBody(resp)
 	if err != nil {
 		return err
 	}
-	bodyStr := string(body)
 
 	// Check for login failure indicators
 	bodyLower := strings.ToLower(bodyStr)
@@ -165,58 +176,54 @@ func (c *Client) GetSessionInfo() (*SessionInfo, error) {
 	return c.sessionManager.GetSessionInfo()
 }
 
-// GetProjectsAndActivities retrieves projects and activities for a date.
+// GetProjectsAndActivities retrieves projects and activities for a date,
+// waiting for the throttle and retrying when the server asks to slow
+// down, as week fetches do.
 func (c *Client) GetProjectsAndActivities(date string) (*ProjectsAndActivities, error) {
 	if !c.loggedIn {
 		return nil, ErrNotLoggedIn
 	}
 
 	activitiesURL := c.cfg.BaseURL + "/Timecard/timecard_week/daychoose.jsp?cho_date=" + url.QueryEscape(date)
-	req, err := http.NewRequest("GET", activitiesURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	c.setCommonHeaders(req)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.getWithRetry(context.Background(), activitiesURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.readBody(resp)
 	if err != nil {
 		return nil, err
 	}
 
-	return ParseProjectsAndActivities(string(body), date), nil
+	return ParseProjectsAndActivities(body, date), nil
 }
 
 // GetWeekTimecard retrieves the week timecard for a given start date.
 func (c *Client) GetWeekTimecard(weekStartDate string) (*WeekTimecard, error) {
+	return c.getWeekTimecard(context.Background(), weekStartDate)
+}
+
+// getWeekTimecard retrieves a week timecard, waiting for the throttle and
+// retrying when the server asks to slow down.
+func (c *Client) getWeekTimecard(ctx context.Context, weekStartDate string) (*WeekTimecard, error) {
 	if !c.loggedIn {
 		return nil, ErrNotLoggedIn
 	}
 
 	weekURL := c.cfg.BaseURL + "/Timecard/timecard_week/daychoose.jsp?cho_date=" + url.QueryEscape(weekStartDate)
-	req, err := http.NewRequest("GET", weekURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	c.setCommonHeaders(req)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.getWithRetry(ctx, weekURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get week timecard: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.readBody(resp)
 	if err != nil {
 		return nil, err
 	}
 
-	return ParseWeekTimecard(string(body), weekStartDate), nil
+	return ParseWeekTimecard(body, weekStartDate), nil
 }
 
 // SaveEntry represents an entry to save.
@@ -229,23 +236,104 @@ type SaveEntry struct {
 
 // SaveDayEntry represents a day entry to save.
 type SaveDayEntry struct {
-	Hours    interface{} `json:"hours"` // Can be float64 or empty string
-	Note     string      `json:"note"`
-	Progress int         `json:"progress"`
+	Hours    Hours  `json:"hours"`
+	Note     string `json:"note"`
+	Progress int    `json:"progress"`
+}
+
+// weekAction identifies the button pressed when posting the week form;
+// weekinfo_deal.jsp dispatches on which button field is present.
+type weekAction struct {
+	name  string // button field name
+	value string // button label as posted by the browser
+	verb  string // used in error messages
 }
 
+// actionSave presses the save button. The submit button differs between
+// TCRS versions and is taken from the week form (see findSubmitButton).
+var actionSave = weekAction{name: "save2", value: " save ", verb: "save"}
+
 // SaveWeekTimecard saves timecard entries for a week.
 func (c *Client) SaveWeekTimecard(weekStartDate string, entries []SaveEntry) error {
 	if !c.loggedIn {
 		return ErrNotLoggedIn
 	}
 
-	// First get projects to ensure we have the latest data
-	_, err := c.GetProjectsAndActivities(weekStartDate)
+	// First load the week to ensure we have the latest data and that it
+	// still accepts changes
+	week, err := c.GetWeekTimecard(weekStartDate)
 	if err != nil {
-		return fmt.Errorf("failed to get projects before save: %w", err)
+		return fmt.Errorf("failed to get week before save: %w", err)
+	}
+	entries = CompactEntries(entries)
+	if err := week.CheckSave(entries); err != nil {
+		return err
+	}
+	if err := ValidateEntries(weekStartDate, entries, c.cfg.MaxDailyHours, c.cfg.HoursStep); err != nil {
+		return err
 	}
 
+	return c.postWeekForm(weekStartDate, entries, week.RowCapacity, actionSave)
+}
+
+// SubmitWeekTimecard sends the week's saved entries for approval. The
+// entries currently stored in TCRS are re-posted with the submit button
+// of the week form, then the week is fetched again to confirm it shows as
+// submitted. Returns ErrNoSubmitButton if the form has no submit button.
+func (c *Client) SubmitWeekTimecard(weekStartDate string) error {
+	if !c.loggedIn {
+		return ErrNotLoggedIn
+	}
+
+	week, err := c.GetWeekTimecard(weekStartDate)
+	if err != nil {
+		return fmt.Errorf("failed to get week before submit: %w", err)
+	}
+	if err := week.checkWritable(); err != nil {
+		return err
+	}
+	if err := week.checkAligned(); err != nil {
+		return err
+	}
+	if len(week.Entries) == 0 {
+		return ErrNothingToSubmit
+	}
+
+	if week.submitButton.name == "" {
+		return ErrNoSubmitButton
+	}
+
+	entries := CompactEntries(week.SaveEntries())
+	if err := checkRowCapacity(entries, week.RowCapacity); err != nil {
+		return err
+	}
+
+	if err := c.postWeekForm(weekStartDate, entries, week.RowCapacity, week.submitButton); err != nil {
+		return err
+	}
+
+	// The server answers a post it did not take as a submit like a save,
+	// so check the week's state instead of trusting the response
+	after, err := c.GetWeekTimecard(weekStartDate)
+	if err != nil {
+		return fmt.Errorf("failed to get week after submit: %w", err)
+	}
+	switch after.Status {
+	case WeekStatusSubmitted, WeekStatusApproved:
+		return nil
+	case WeekStatusUnknown:
+		if after.Locked {
+			return nil
+		}
+		return ErrSubmitNotConfirmed
+	}
+	return fmt.Errorf("%w (status: %s)", ErrSubmitNotConfirmed, after.Status)
+}
+
+// postWeekForm posts the week form to weekinfo_deal.jsp with the given
+// entries, pressing the button of the given action. Rows past the entries
+// are sent empty up to the form's row capacity.
+func (c *Client) postWeekForm(weekStartDate string, entries []SaveEntry, capacity int, action weekAction) error {
 	saveURL := c.cfg.BaseURL + "/Timecard/timecard_week/weekinfo_deal.jsp"
 
 	// Build form parameters
@@ -273,29 +361,15 @@ func (c *Client) SaveWeekTimecard(weekStartDate string, entries []SaveEntry) err
 		for dayIdx := 0; dayIdx < 7 && dayIdx < len(entry.Days); dayIdx++ {
 			day := entry.Days[dayIdx]
 
-			// Hours
-			hoursStr := ""
-			switch v := day.Hours.(type) {
-			case float64:
-				hoursStr = strconv.FormatFloat(v, 'f', -1, 64)
-				dailyTotals[dayIdx] += v
-			case int:
-				hoursStr = strconv.Itoa(v)
-				dailyTotals[dayIdx] += float64(v)
-			case string:
-				hoursStr = v
-				if h, err := strconv.ParseFloat(v, 64); err == nil {
-					dailyTotals[dayIdx] += h
-				}
-			}
-			params[fmt.Sprintf("record%d_%d", idx, dayIdx)] = hoursStr
+			dailyTotals[dayIdx] += day.Hours.Float()
+			params[fmt.Sprintf("record%d_%d", idx, dayIdx)] = day.Hours.String()
 			params[fmt.Sprintf("note%d_%d", idx, dayIdx)] = day.Note
 			params[fmt.Sprintf("progress%d_%d", idx, dayIdx)] = strconv.Itoa(day.Progress)
 		}
 	}
 
-	// Fill empty entries (up to 25 projects)
-	for emptyIdx := len(entries); emptyIdx < 25; emptyIdx++ {
+	// Fill empty entries (up to the row capacity)
+	for emptyIdx := len(entries); emptyIdx < capacity; emptyIdx++ {
 		params[fmt.Sprintf("project%d", emptyIdx)] = ""
 		params[fmt.Sprintf("activity%d", emptyIdx)] = ""
 		params[fmt.Sprintf("actprogress%d", emptyIdx)] = ""
@@ -311,7 +385,7 @@ func (c *Client) SaveWeekTimecard(weekStartDate string, entries []SaveEntry) err
 	}
 
 	// Add overtime entries (all zeros)
-	for idx := 0; idx < 25; idx++ {
+	for idx := 0; idx < capacity; idx++ {
 		params[fmt.Sprintf("overactprogress%d", idx)] = "0"
 		for dayIdx := 0; dayIdx < 7; dayIdx++ {
 			params[fmt.Sprintf("overrecord%d_%d", idx, dayIdx)] = ""
@@ -327,9 +401,9 @@ func (c *Client) SaveWeekTimecard(weekStartDate string, entries []SaveEntry) err
 
 	// Build form data with specific order (mimicking browser behavior)
 	formParts := []string{
-		"save2=" + url.QueryEscape(" save "),
+		action.name + "=" + c.formEscape(action.value),
 		"caller=this_week",
-		"cdate=" + url.QueryEscape(weekStartDate),
+		"cdate=" + c.formEscape(weekStartDate),
 	}
 
 	// Add project/activity/record/note/progress params (sorted)
@@ -345,13 +419,13 @@ func (c *Client) SaveWeekTimecard(weekStartDate string, entries []SaveEntry) err
 	}
 	sort.Strings(projectKeys)
 	for _, key := range projectKeys {
-		formParts = append(formParts, key+"="+url.QueryEscape(params[key]))
+		formParts = append(formParts, key+"="+c.formEscape(params[key]))
 	}
 
 	// Add norTotal params
 	for dayIdx := 0; dayIdx < 7; dayIdx++ {
 		key := fmt.Sprintf("norTotal%d", dayIdx)
-		formParts = append(formParts, key+"="+url.QueryEscape(params[key]))
+		formParts = append(formParts, key+"="+c.formEscape(params[key]))
 	}
 
 	// Add second caller param
@@ -366,7 +440,7 @@ func (c *Client) SaveWeekTimecard(weekStartDate string, entries []SaveEntry) err
 	}
 	sort.Strings(overKeys)
 	for _, key := range overKeys {
-		formParts = append(formParts, key+"="+url.QueryEscape(params[key]))
+		formParts = append(formParts, key+"="+c.formEscape(params[key]))
 	}
 
 	formData := strings.Join(formParts, "&")
@@ -382,21 +456,16 @@ func (c *Client) SaveWeekTimecard(weekStartDate string, entries []SaveEntry) err
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("save request failed: %w", err)
+		return fmt.Errorf("%s request failed: %w", action.verb, err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.readBody(resp)
 	if err != nil {
 		return err
 	}
 
-	bodyLower := strings.ToLower(string(body))
-	if strings.Contains(bodyLower, "error") || strings.Contains(bodyLower, "failed") {
-		return fmt.Errorf("server indicated save failure")
-	}
-
-	return nil
+	return parseSaveResponse(body, weekStartDate, entries, action)
 }
 
 // GetUserID returns the user ID.
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultRowCapacity is the number of project rows the week form has when
+// the page doesn't tell otherwise.
+const DefaultRowCapacity = 25
+
+// CompactEntries drops entries without a project and merges entries that
+// share the same project and activity. Hours of merged entries are added
+// up per day, notes are joined and the highest progress wins. The order of
+// first appearance is kept.
+func CompactEntries(entries []SaveEntry) []SaveEntry {
+	result := make([]SaveEntry, 0, len(entries))
+	index := make(map[string]int)
+
+	for _, entry := range entries {
+		if entry.ProjectID == "" {
+			continue
+		}
+
+		key := entry.ProjectID + "$" + entry.ActivityID
+		idx, ok := index[key]
+		if !ok {
+			index[key] = len(result)
+			days := make([]SaveDayEntry, len(entry.Days))
+			copy(days, entry.Days)
+			entry.Days = days
+			result = append(result, entry)
+			continue
+		}
+
+		merged := &result[idx]
+		if entry.Progress > merged.Progress {
+			merged.Progress = entry.Progress
+		}
+		for dayIdx, day := range entry.Days {
+			if dayIdx >= len(merged.Days) {
+				merged.Days = append(merged.Days, day)
+				continue
+			}
+			merged.Days[dayIdx] = mergeDay(merged.Days[dayIdx], day)
+		}
+	}
+
+	return result
+}
+
+// OverlayEntries returns the existing entries with updates laid over
+// them: days of an update replace the same project/activity's day, rows
+// and days not mentioned in updates are kept, and new project/activity
+// rows are appended. Empty days in updates leave existing days alone.
+func OverlayEntries(existing, updates []SaveEntry) []SaveEntry {
+	result := CompactEntries(existing)
+	index := make(map[string]int, len(result))
+	for i, entry := range result {
+		index[entry.ProjectID+"$"+entry.ActivityID] = i
+	}
+
+	for _, update := range CompactEntries(updates) {
+		key := update.ProjectID + "$" + update.ActivityID
+		idx, ok := index[key]
+		if !ok {
+			index[key] = len(result)
+			result = append(result, update)
+			continue
+		}
+
+		target := &result[idx]
+		for dayIdx, day := range update.Days {
+			if day.Hours.IsEmpty() && day.Note == "" {
+				continue
+			}
+			for len(target.Days) <= dayIdx {
+				target.Days = append(target.Days, SaveDayEntry{})
+			}
+			target.Days[dayIdx] = day
+		}
+	}
+	return result
+}
+
+// DayChange is a day of a project/activity that differs between two sets
+// of entries. Before or After is empty for days added or cleared.
+type DayChange struct {
+	ProjectID  string
+	ActivityID string
+	Day        int // column index in the week
+	Before     SaveDayEntry
+	After      SaveDayEntry
+}
+
+// DiffEntries lists the days whose hours or note differ between before
+// and after, in the row order of after followed by rows only in before.
+func DiffEntries(before, after []SaveEntry) []DayChange {
+	before, after = CompactEntries(before), CompactEntries(after)
+	old := make(map[string]SaveEntry, len(before))
+	for _, entry := range before {
+		old[entry.ProjectID+"$"+entry.ActivityID] = entry
+	}
+
+	var changes []DayChange
+	diff := func(a, b SaveEntry, projectID, activityID string) {
+		for day := 0; day < 7; day++ {
+			da, db := dayAt(a, day), dayAt(b, day)
+			if da.Hours.Float() != db.Hours.Float() || strings.TrimSpace(da.Note) != strings.TrimSpace(db.Note) {
+				changes = append(changes, DayChange{ProjectID: projectID, ActivityID: activityID, Day: day, Before: da, After: db})
+			}
+		}
+	}
+	seen := make(map[string]bool, len(after))
+	for _, entry := range after {
+		key := entry.ProjectID + "$" + entry.ActivityID
+		seen[key] = true
+		diff(old[key], entry, entry.ProjectID, entry.ActivityID)
+	}
+	for _, entry := range before {
+		if !seen[entry.ProjectID+"$"+entry.ActivityID] {
+			diff(entry, SaveEntry{}, entry.ProjectID, entry.ActivityID)
+		}
+	}
+	return changes
+}
+
+func dayAt(entry SaveEntry, day int) SaveDayEntry {
+	if day < len(entry.Days) {
+		return entry.Days[day]
+	}
+	return SaveDayEntry{}
+}
+
+// mergeDay combines two day entries of the same project and activity.
+func mergeDay(a, b SaveDayEntry) SaveDayEntry {
+	a.Hours = a.Hours.Add(b.Hours)
+
+	notes := make([]string, 0, 2)
+	for _, n := range []string{a.Note, b.Note} {
+		if n = strings.TrimSpace(n); n != "" && !containsString(notes, n) {
+			notes = append(notes, n)
+		}
+	}
+	a.Note = strings.Join(notes, "; ")
+
+	if b.Progress > a.Progress {
+		a.Progress = b.Progress
+	}
+	return a
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRowCapacity returns ErrTooManyRows if entries don't fit into the
+// week form.
+func checkRowCapacity(entries []SaveEntry, capacity int) error {
+	if len(entries) > capacity {
+		return fmt.Errorf("%w: %d project/activity rows, the week form holds %d", ErrTooManyRows, len(entries), capacity)
+	}
+	return nil
+}
+
+// ValidateEntries checks every cell with Hours.Validate and that no day
+// adds up to more than max hours across all entries. Errors name the
+// column's date in the week starting at weekStartDate.
+func ValidateEntries(weekStartDate string, entries []SaveEntry, max, step float64) error {
+	dailyTotals := make([]float64, 7)
+	for idx, entry := range entries {
+		for dayIdx := 0; dayIdx < 7 && dayIdx < len(entry.Days); dayIdx++ {
+			hours := entry.Days[dayIdx].Hours
+			if err := hours.Validate(max, step); err != nil {
+				return fmt.Errorf("row %d (project %s), %s: %w", idx+1, entry.ProjectID, columnDate(weekStartDate, dayIdx), err)
+			}
+			dailyTotals[dayIdx] += hours.Float()
+		}
+	}
+
+	if max > 0 {
+		for dayIdx, total := range dailyTotals {
+			if total > max {
+				return fmt.Errorf("%s: %s hours in total exceed the maximum of %s per day",
+					columnDate(weekStartDate, dayIdx), NewHours(total), NewHours(max))
+			}
+		}
+	}
+	return nil
+}
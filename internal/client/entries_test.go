@@ -0,0 +1,169 @@
+package client
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// week builds a SaveEntry from day cells written as "hours" or
+// "hours|note"; "" is an empty day.
+func week(projectID, activityID string, cells ...string) SaveEntry {
+	entry := SaveEntry{ProjectID: projectID, ActivityID: activityID}
+	for _, cell := range cells {
+		hours, note, _ := strings.Cut(cell, "|")
+		h, err := ParseHours(hours)
+		if err != nil {
+			panic(err)
+		}
+		entry.Days = append(entry.Days, SaveDayEntry{Hours: h, Note: note})
+	}
+	return entry
+}
+
+// cells formats entries back into the notation of week.
+func cells(entries []SaveEntry) []string {
+	var out []string
+	for _, entry := range entries {
+		row := []string{entry.ProjectID + "/" + entry.ActivityID}
+		for _, day := range entry.Days {
+			cell := day.Hours.String()
+			if day.Note != "" {
+				cell += "|" + day.Note
+			}
+			row = append(row, cell)
+		}
+		out = append(out, strings.Join(row, " "))
+	}
+	return out
+}
+
+func TestCompactEntries(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []SaveEntry
+		want []string
+	}{
+		{"nothing to merge", []SaveEntry{week("1", "a", "8"), week("2", "", "", "4")}, []string{"1/a 8", "2/  4"}},
+		{"no project dropped", []SaveEntry{week("", "a", "8"), week("1", "a", "2")}, []string{"1/a 2"}},
+		{"same project and activity merged", []SaveEntry{
+			week("1", "a", "2|review", "", "1"),
+			week("2", "b", "3"),
+			week("1", "a", "1.5|fix", "", "", "4"),
+		}, []string{"1/a 3.5|review; fix  1 4", "2/b 3"}},
+		{"repeated notes joined once", []SaveEntry{week("1", "a", "1|review"), week("1", "a", "1|review ")}, []string{"1/a 2|review"}},
+		{"empty days stay empty", []SaveEntry{week("1", "a", "", "0"), week("1", "a", "", "")}, []string{"1/a  0"}},
+		{"other activity kept apart", []SaveEntry{week("1", "a", "1"), week("1", "b", "1")}, []string{"1/a 1", "1/b 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cells(CompactEntries(tt.in)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompactEntries = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactEntriesProgressAndCopy(t *testing.T) {
+	a, b := week("1", "a", "1"), week("1", "a", "1")
+	a.Progress, b.Progress = 40, 60
+	a.Days[0].Progress, b.Days[0].Progress = 10, 5
+	got := CompactEntries([]SaveEntry{a, b})
+	if got[0].Progress != 60 || got[0].Days[0].Progress != 10 {
+		t.Errorf("progress = %d, day %d, want 60, 10", got[0].Progress, got[0].Days[0].Progress)
+	}
+	if a.Days[0].Hours.String() != "1" {
+		t.Errorf("CompactEntries changed its input: %q", a.Days[0].Hours)
+	}
+}
+
+func TestOverlayEntries(t *testing.T) {
+	existing := []SaveEntry{
+		week("1", "a", "8", "8|standup", "8"),
+		week("2", "", "", "", "", "2"),
+	}
+	tests := []struct {
+		name    string
+		updates []SaveEntry
+		want    []string
+	}{
+		{"no updates", nil, []string{"1/a 8 8|standup 8", "2/    2"}},
+		{"days replaced, others kept", []SaveEntry{week("1", "a", "", "6|moved", "", "", "1")},
+			[]string{"1/a 8 6|moved 8  1", "2/    2"}},
+		{"zero replaces", []SaveEntry{week("2", "", "", "", "", "0")}, []string{"1/a 8 8|standup 8", "2/    0"}},
+		{"note alone replaces the day", []SaveEntry{week("1", "a", "|remote")}, []string{"1/a |remote 8|standup 8", "2/    2"}},
+		{"new row appended", []SaveEntry{week("3", "c", "", "", "1")}, []string{"1/a 8 8|standup 8", "2/    2", "3/c   1"}},
+		{"duplicate updates merged first", []SaveEntry{week("1", "a", "2"), week("1", "a", "3")},
+			[]string{"1/a 5 8|standup 8", "2/    2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cells(OverlayEntries(existing, tt.updates)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OverlayEntries = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := cells(existing); got[0] != "1/a 8 8|standup 8" {
+		t.Errorf("OverlayEntries changed existing: %q", got)
+	}
+}
+
+func TestDiffEntries(t *testing.T) {
+	before := []SaveEntry{week("1", "a", "8", "8|standup"), week("2", "", "1")}
+	after := []SaveEntry{week("3", "c", "", "2"), week("1", "a", "8", "8|standup ", "", "", "", "", "4")}
+
+	var got []string
+	for _, c := range DiffEntries(before, after) {
+		got = append(got, c.ProjectID+"/"+c.ActivityID+" day "+string(rune('0'+c.Day))+": "+
+			c.Before.Hours.String()+" -> "+c.After.Hours.String())
+	}
+	want := []string{
+		"3/c day 1:  -> 2",
+		"1/a day 6:  -> 4",
+		"2/ day 0: 1 -> ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffEntries = %q, want %q", got, want)
+	}
+
+	if changes := DiffEntries(before, before); len(changes) != 0 {
+		t.Errorf("DiffEntries of equal entries = %v", changes)
+	}
+	// An empty day and an explicit 0 both count as no hours
+	if changes := DiffEntries([]SaveEntry{week("1", "a", "")}, []SaveEntry{week("1", "a", "0")}); len(changes) != 0 {
+		t.Errorf("DiffEntries(empty, 0) = %v", changes)
+	}
+}
+
+func TestValidateEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []SaveEntry
+		want    string
+	}{
+		{"valid", []SaveEntry{week("1", "a", "8", "7.5"), week("2", "", "", "0.5")}, ""},
+		{"bad step", []SaveEntry{week("1", "a", "8"), week("2", "", "", "7.25")},
+			"row 2 (project 2), 2025-01-14 (Tue): hours 7.25 are not a multiple of 0.5"},
+		{"negative", []SaveEntry{week("1", "a", "", "", "-1")}, "row 1 (project 1), 2025-01-15 (Wed): hours -1 must not be negative"},
+		{"cell over max", []SaveEntry{week("1", "a", "25")}, "exceed the maximum of 24 per day"},
+		{"day total over max", []SaveEntry{week("1", "a", "", "", "", "", "", "", "16"), week("2", "", "", "", "", "", "", "", "9")},
+			"2025-01-19 (Sun): 25 hours in total exceed the maximum of 24 per day"},
+	}
+	for _, tt := range tests {
+		err := ValidateEntries("2025-01-13", tt.entries, 24, 0.5)
+		if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("%s: ValidateEntries = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestCheckRowCapacity(t *testing.T) {
+	entries := []SaveEntry{week("1", "a"), week("2", "b"), week("3", "c")}
+	if err := checkRowCapacity(entries, 3); err != nil {
+		t.Errorf("checkRowCapacity(3 rows, 3) = %v", err)
+	}
+	if err := checkRowCapacity(entries, 2); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("checkRowCapacity(3 rows, 2) = %v, want ErrTooManyRows", err)
+	}
+}
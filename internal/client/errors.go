@@ -15,4 +15,16 @@ var (
 	ErrLoginFailed = errors.New("login failed")
 	// ErrInvalidCredentials indicates invalid credentials.
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrWeekLocked indicates the week is submitted, approved or otherwise read-only.
+	ErrWeekLocked = errors.New("week is locked")
+	// ErrNothingToSubmit indicates a week without saved entries was submitted.
+	ErrNothingToSubmit = errors.New("week has no saved entries to submit")
+	// ErrNoSubmitButton indicates the week form has no send-for-approval button.
+	ErrNoSubmitButton = errors.New("week form has no submit button")
+	// ErrSubmitNotConfirmed indicates the week did not show as submitted after submitting it.
+	ErrSubmitNotConfirmed = errors.New("week does not show as submitted")
+	// ErrTooManyRows indicates more entries than the week form has rows for.
+	ErrTooManyRows = errors.New("too many rows for week form")
+	// ErrWeekMisaligned indicates the server's week starts on a different day than requested.
+	ErrWeekMisaligned = errors.New("week start does not match the server")
 )
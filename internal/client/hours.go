@@ -0,0 +1,155 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hours is the number of hours in one timecard cell. The zero value is an
+// empty cell, which TCRS treats differently from an explicit 0.
+type Hours struct {
+	value float64
+	set   bool
+}
+
+// NewHours returns Hours holding h.
+func NewHours(h float64) Hours {
+	return Hours{value: h, set: true}
+}
+
+// ParseHours parses an hours value. It accepts "" (empty), decimal
+// numbers ("7.5"), Go durations ("1h30m", "45m", "8h") and clock notation
+// ("7:30").
+func ParseHours(s string) (Hours, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Hours{}, nil
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return Hours{}, fmt.Errorf("invalid hours %q", s)
+		}
+		return NewHours(f), nil
+	}
+
+	if h, m, ok := strings.Cut(s, ":"); ok {
+		// Atoi takes "-0" for 0, so signs are rejected here rather than
+		// by the range checks
+		hours, errH := strconv.Atoi(h)
+		minutes, errM := strconv.Atoi(m)
+		if !isDigits(h) || !isDigits(m) || errH != nil || errM != nil || minutes >= 60 || len(m) != 2 {
+			return Hours{}, fmt.Errorf("invalid hours %q (expected H:MM)", s)
+		}
+		return NewHours(float64(hours) + float64(minutes)/60), nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return NewHours(d.Hours()), nil
+	}
+
+	return Hours{}, fmt.Errorf("invalid hours %q (use a number, 1h30m or 7:30)", s)
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty reports whether the cell is empty.
+func (h Hours) IsEmpty() bool {
+	return !h.set
+}
+
+// Float returns the number of hours; empty cells count as 0.
+func (h Hours) Float() float64 {
+	return h.value
+}
+
+// String formats the hours as posted to TCRS: the shortest decimal
+// representation, or "" for an empty cell.
+func (h Hours) String() string {
+	if !h.set {
+		return ""
+	}
+	return strconv.FormatFloat(h.value, 'f', -1, 64)
+}
+
+// Add returns the sum of two cells. The sum is empty only if both are.
+func (h Hours) Add(o Hours) Hours {
+	if !h.set && !o.set {
+		return Hours{}
+	}
+	return NewHours(h.value + o.value)
+}
+
+// Validate checks that the hours are non-negative, no more than max and a
+// multiple of step. A max or step of 0 disables that check.
+func (h Hours) Validate(max, step float64) error {
+	if !h.set {
+		return nil
+	}
+	if h.value < 0 {
+		return fmt.Errorf("hours %s must not be negative", h)
+	}
+	if max > 0 && h.value > max {
+		return fmt.Errorf("hours %s exceed the maximum of %s per day", h, NewHours(max))
+	}
+	if step > 0 {
+		n := h.value / step
+		if math.Abs(n-math.Round(n)) > 1e-9 {
+			return fmt.Errorf("hours %s are not a multiple of %s", h, NewHours(step))
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the hours as a number, or "" for an empty cell.
+func (h Hours) MarshalJSON() ([]byte, error) {
+	if !h.set {
+		return []byte(`""`), nil
+	}
+	return []byte(h.String()), nil
+}
+
+// UnmarshalJSON accepts a number, null, or a string in any form accepted
+// by ParseHours.
+func (h *Hours) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		*h = Hours{}
+		return nil
+	}
+
+	if strings.HasPrefix(s, `"`) {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		parsed, err := ParseHours(str)
+		if err != nil {
+			return err
+		}
+		*h = parsed
+		return nil
+	}
+
+	var f float64
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("invalid hours %s", s)
+	}
+	*h = NewHours(f)
+	return nil
+}
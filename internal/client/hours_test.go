@@ -0,0 +1,133 @@
+package client
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseHours(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  string
+		empty bool
+	}{
+		{"", "", true},
+		{"   ", "", true},
+		{"8", "8", false},
+		{"7.5", "7.5", false},
+		{" 0.25 ", "0.25", false},
+		{"0", "0", false},
+		{".5", "0.5", false},
+		{"-1", "-1", false}, // rejected by Validate, not by parsing
+		{"7:30", "7.5", false},
+		{"0:15", "0.25", false},
+		{"10:00", "10", false},
+		{"7h30m", "7.5", false},
+		{"45m", "0.75", false},
+		{"8h", "8", false},
+		{"1h15m", "1.25", false},
+	}
+	for _, tt := range tests {
+		got, err := ParseHours(tt.in)
+		if err != nil {
+			t.Errorf("ParseHours(%q): %v", tt.in, err)
+			continue
+		}
+		if got.String() != tt.want || got.IsEmpty() != tt.empty {
+			t.Errorf("ParseHours(%q) = %q (empty %v), want %q (empty %v)", tt.in, got, got.IsEmpty(), tt.want, tt.empty)
+		}
+	}
+
+	for _, in := range []string{"abc", "NaN", "Inf", "-Inf", "7:3", "7:60", "7:300", ":30", "7:", "-0:30", "+7:30", "7:-5", "1:2:3", "7 h", "seven"} {
+		if h, err := ParseHours(in); err == nil {
+			t.Errorf("ParseHours(%q) = %v, want an error", in, h)
+		}
+	}
+}
+
+func TestHoursValidate(t *testing.T) {
+	tests := []struct {
+		hours     Hours
+		max, step float64
+		want      string // error substring, or "" for none
+	}{
+		{Hours{}, 24, 0.5, ""},
+		{NewHours(0), 24, 0.5, ""},
+		{NewHours(8), 24, 0.5, ""},
+		{NewHours(24), 24, 0.5, ""},
+		{NewHours(7.25), 24, 0.25, ""},
+		{NewHours(0.1 + 0.2), 24, 0.1, ""}, // float error within tolerance
+		{NewHours(7.25), 24, 0.5, "hours 7.25 are not a multiple of 0.5"},
+		{NewHours(7.3), 0, 0.25, "not a multiple of 0.25"},
+		{NewHours(24.5), 24, 0.5, "hours 24.5 exceed the maximum of 24 per day"},
+		{NewHours(30), 0, 0, ""},
+		{NewHours(-1), 24, 0.5, "hours -1 must not be negative"},
+		{NewHours(-0.5), 0, 0, "must not be negative"},
+		{NewHours(1.33), 24, 0, ""},
+	}
+	for _, tt := range tests {
+		err := tt.hours.Validate(tt.max, tt.step)
+		if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("Hours(%s).Validate(%v, %v) = %v, want %q", tt.hours, tt.max, tt.step, err, tt.want)
+		}
+	}
+}
+
+func TestHoursJSON(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  string
+		empty bool
+	}{
+		{`8`, "8", false},
+		{`7.5`, "7.5", false},
+		{`0`, "0", false},
+		{`null`, "", true},
+		{`""`, "", true},
+		{`"7:30"`, "7.5", false},
+		{`"7h30m"`, "7.5", false},
+		{`" 6.25 "`, "6.25", false},
+	}
+	for _, tt := range tests {
+		var h Hours
+		if err := json.Unmarshal([]byte(tt.in), &h); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if h.String() != tt.want || h.IsEmpty() != tt.empty {
+			t.Errorf("Unmarshal(%s) = %q (empty %v), want %q (empty %v)", tt.in, h, h.IsEmpty(), tt.want, tt.empty)
+		}
+	}
+
+	for _, in := range []string{`"abc"`, `"7:3"`, `true`, `[8]`, `{}`} {
+		var h Hours
+		if err := json.Unmarshal([]byte(in), &h); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want an error", in, h)
+		}
+	}
+
+	data, err := json.Marshal([]Hours{NewHours(7.5), {}, NewHours(0), NewHours(8)})
+	if err != nil || string(data) != `[7.5,"",0,8]` {
+		t.Errorf("Marshal = %s, %v, want [7.5,\"\",0,8]", data, err)
+	}
+}
+
+func TestHoursAdd(t *testing.T) {
+	tests := []struct {
+		a, b  Hours
+		want  string
+		empty bool
+	}{
+		{Hours{}, Hours{}, "", true},
+		{NewHours(2), Hours{}, "2", false},
+		{Hours{}, NewHours(0), "0", false},
+		{NewHours(2.5), NewHours(0.25), "2.75", false},
+	}
+	for _, tt := range tests {
+		got := tt.a.Add(tt.b)
+		if got.String() != tt.want || got.IsEmpty() != tt.empty {
+			t.Errorf("%q.Add(%q) = %q (empty %v), want %q", tt.a, tt.b, got, got.IsEmpty(), tt.want)
+		}
+	}
+}
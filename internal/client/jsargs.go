@@ -0,0 +1,163 @@
+package client
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// actAppendArgs is the number of arguments an act.append call carries:
+// project ID, activity name, is-bottom flag, UID and progress.
+const actAppendArgs = 5
+
+// parseActAppendCalls finds every act.append(...) call in the page and
+// returns its arguments. Calls with fewer than five arguments or an empty
+// project ID are skipped.
+func parseActAppendCalls(src string) [][]string {
+	calls := make([][]string, 0)
+	for _, args := range findJSCalls(src, "act.append(") {
+		if len(args) < actAppendArgs || strings.TrimSpace(args[0]) == "" {
+			continue
+		}
+		for j := range args {
+			args[j] = html.UnescapeString(args[j])
+		}
+		args[0] = strings.TrimSpace(args[0])
+		calls = append(calls, args)
+	}
+
+	return calls
+}
+
+// findJSCalls returns the arguments of every call to fn (e.g. "alert(")
+// in the page's JavaScript.
+func findJSCalls(src, fn string) [][]string {
+	calls := make([][]string, 0)
+	for pos := 0; ; {
+		i := strings.Index(src[pos:], fn)
+		if i < 0 {
+			break
+		}
+		pos += i + len(fn)
+
+		args, next, ok := tokenizeJSArgs(src, pos)
+		if !ok {
+			continue
+		}
+		pos = next
+		calls = append(calls, args)
+	}
+	return calls
+}
+
+// tokenizeJSArgs reads a comma separated JavaScript argument list starting
+// just after the opening parenthesis. Single and double quoted strings are
+// unescaped; bare tokens (numbers, true/false) are returned trimmed. It
+// returns the arguments and the offset just past the closing parenthesis.
+func tokenizeJSArgs(src string, pos int) ([]string, int, bool) {
+	args := make([]string, 0, actAppendArgs)
+	var cur strings.Builder
+	quoted := false
+
+	for pos < len(src) {
+		c := src[pos]
+		switch {
+		case c == '\'' || c == '"':
+			s, next, ok := readJSString(src, pos)
+			if !ok {
+				return nil, pos, false
+			}
+			if !quoted {
+				// Drop whitespace preceding the literal
+				cur.Reset()
+			}
+			cur.WriteString(s)
+			quoted = true
+			pos = next
+		case c == ',' || c == ')':
+			arg := cur.String()
+			if !quoted {
+				arg = strings.TrimSpace(arg)
+			}
+			args = append(args, arg)
+			cur.Reset()
+			quoted = false
+			pos++
+			if c == ')' {
+				return args, pos, true
+			}
+		case c == ';':
+			// Unterminated call; give up on this one.
+			return nil, pos, false
+		default:
+			if !quoted {
+				cur.WriteByte(c)
+			}
+			pos++
+		}
+	}
+
+	return nil, pos, false
+}
+
+// readJSString reads a quoted JavaScript string literal starting at the
+// opening quote and returns its unescaped value and the offset past the
+// closing quote.
+func readJSString(src string, pos int) (string, int, bool) {
+	quote := src[pos]
+	pos++
+
+	var b strings.Builder
+	for pos < len(src) {
+		c := src[pos]
+		switch c {
+		case quote:
+			return b.String(), pos + 1, true
+		case '\n':
+			return "", pos, false
+		case '\\':
+			if pos+1 >= len(src) {
+				return "", pos, false
+			}
+			pos++
+			switch e := src[pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'u':
+				if pos+4 < len(src) {
+					if r, err := strconv.ParseUint(src[pos+1:pos+5], 16, 32); err == nil {
+						b.WriteRune(rune(r))
+						pos += 4
+						break
+					}
+				}
+				b.WriteByte(e)
+			case 'x':
+				if pos+2 < len(src) {
+					if r, err := strconv.ParseUint(src[pos+1:pos+3], 16, 8); err == nil {
+						b.WriteRune(rune(r))
+						pos += 2
+						break
+					}
+				}
+				b.WriteByte(e)
+			default:
+				// \' \" \\ and unknown escapes map to the character itself
+				_, size := utf8.DecodeRuneInString(src[pos:])
+				b.WriteString(src[pos : pos+size])
+				pos += size - 1
+			}
+			pos++
+		default:
+			b.WriteByte(c)
+			pos++
+		}
+	}
+
+	return "", pos, false
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeJSArgs(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+		rest string
+	}{
+		{`1, 2)`, []string{"1", "2"}, ""},
+		{`)`, []string{""}, ""},
+		{`'12345', "Dev", true, 'u1', 0);next`, []string{"12345", "Dev", "true", "u1", "0"}, ";next"},
+		{`  'a, b' , "c)d")`, []string{"a, b", "c)d"}, ""},
+		{`'it\'s', "say \"hi\"", 'back\\slash')`, []string{"it's", `say "hi"`, `back\slash`}, ""},
+		{`'line\nbreak\ttab')`, []string{"line\nbreak\ttab"}, ""},
+		{`'開發', '\x41\x42')`, []string{"開發", "AB"}, ""},
+		{`'資訊系統', '\q')`, []string{"資訊系統", "q"}, ""},
+		{`'a' + 'b')`, []string{"ab"}, ""},
+		{`x, 'y' )`, []string{"x", "y"}, ""},
+	}
+	for _, tt := range tests {
+		args, next, ok := tokenizeJSArgs(tt.src, 0)
+		if !ok || !reflect.DeepEqual(args, tt.want) || tt.src[next:] != tt.rest {
+			t.Errorf("tokenizeJSArgs(%q) = %q, rest %q, %v, want %q, rest %q", tt.src, args, tt.src[next:], ok, tt.want, tt.rest)
+		}
+	}
+
+	for _, src := range []string{
+		`'unterminated, 1)`,
+		"'broken\nline')",
+		`1, 2; 3)`,
+		`1, 2`,
+		`'trailing\`,
+	} {
+		if args, _, ok := tokenizeJSArgs(src, 0); ok {
+			t.Errorf("tokenizeJSArgs(%q) = %q, want failure", src, args)
+		}
+	}
+}
+
+func TestParseActAppendCalls(t *testing.T) {
+	src := `<script>
+act.append('12345', 'Development', true, 'u1', 0);
+act.append(' 12345 ', 'R&amp;D 測試', false, 'u2', '50');
+act.append('', 'No project', true, 'u3', 0);
+act.append('67890', 'Short');
+act.append('67890', 'broken;
+act.append("67890", "Support", 1, "u4", 10, "extra");
+</script>`
+	want := [][]string{
+		{"12345", "Development", "true", "u1", "0"},
+		{"12345", "R&D 測試", "false", "u2", "50"},
+		{"67890", "Support", "1", "u4", "10", "extra"},
+	}
+	if got := parseActAppendCalls(src); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseActAppendCalls = %q, want %q", got, want)
+	}
+}
+
+func TestFindJSCalls(t *testing.T) {
+	src := `alert('Saved'); x = 1; alert("第 2 列: 工時超過");alert(msg);`
+	want := [][]string{{"Saved"}, {"第 2 列: 工時超過"}, {"msg"}}
+	if got := findJSCalls(src, "alert("); !reflect.DeepEqual(got, want) {
+		t.Errorf("findJSCalls = %q, want %q", got, want)
+	}
+}
@@ -1,7 +1,9 @@
 package client
 
 import (
+	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -25,13 +27,14 @@ type Activity struct {
 	UID         string `json:"uid"`
 	Progress    string `json:"progress"`
 	IndentLevel int    `json:"indent_level"`
+	WBS         string `json:"wbs,omitempty"`
 }
 
 // DayEntry represents a single day's timecard entry.
 type DayEntry struct {
-	Hours    interface{} `json:"hours"` // Can be float64 or empty string
-	Note     string      `json:"note"`
-	Progress int         `json:"progress"`
+	Hours    Hours  `json:"hours"`
+	Note     string `json:"note"`
+	Progress int    `json:"progress"`
 }
 
 // WeekEntry represents a week's timecard entry for a project.
@@ -39,15 +42,23 @@ type WeekEntry struct {
 	ProjectID    string     `json:"project_id"`
 	ProjectName  string     `json:"project_name"`
 	ActivityData string     `json:"activity_data"`
+	ActivityName string     `json:"activity_name,omitempty"`
 	Progress     int        `json:"progress"`
 	Days         []DayEntry `json:"days"`
 }
 
 // WeekTimecard represents a full week's timecard data.
 type WeekTimecard struct {
-	WeekStartDate string      `json:"week_start_date"`
-	Entries       []WeekEntry `json:"entries"`
-	DailyTotals   []float64   `json:"daily_totals"`
+	WeekStartDate    string      `json:"week_start_date"`
+	Status           WeekStatus  `json:"status"`
+	Locked           bool        `json:"locked"`
+	ApproverComments []string    `json:"approver_comments,omitempty"`
+	Dates            []string    `json:"dates,omitempty"` // Column dates shown in the page header
+	RowCapacity      int         `json:"row_capacity"`    // Number of project rows in the form
+	Entries          []WeekEntry `json:"entries"`
+	DailyTotals      []float64   `json:"daily_totals"`
+
+	submitButton weekAction // submit button found on the page, if any
 }
 
 // ProjectsAndActivities represents the result of parsing projects and activities.
@@ -65,161 +76,211 @@ func ParseProjectsAndActivities(htmlContent, date string) *ProjectsAndActivities
 		Projects: make([]Project, 0),
 	}
 
-	// Create project map with activities
-	projectMap := make(map[string]*Project)
+	// Create projects in page order, indexed by ID
+	projectIndex := make(map[string]int)
 	for _, p := range projects {
-		proj := Project{
+		projectIndex[p.ID] = len(result.Projects)
+		result.Projects = append(result.Projects, Project{
 			ID:         p.ID,
 			Name:       p.Name,
 			Activities: make([]Activity, 0),
-		}
-		projectMap[p.ID] = &proj
+		})
 	}
 
-	// Add activities to their respective projects
+	// Add activities to their respective projects, keeping page order
 	for _, act := range activities {
-		if proj, ok := projectMap[act.ProjectID]; ok {
+		if idx, ok := projectIndex[act.ProjectID]; ok {
 			// Use UID as ID for the activity
 			actCopy := act
 			actCopy.ID = act.UID
-			proj.Activities = append(proj.Activities, actCopy)
+			result.Projects[idx].Activities = append(result.Projects[idx].Activities, actCopy)
 		}
 	}
 
-	// Convert map to slice
-	for _, proj := range projectMap {
-		result.Projects = append(result.Projects, *proj)
+	return result
+}
+
+// Project sort keys accepted by SortProjects.
+const (
+	SortByID   = "id"
+	SortByName = "name"
+)
+
+// SortProjects sorts the projects by ID or name. IDs are compared
+// numerically when both are numbers. The sort is stable and leaves the
+// activity order within each project untouched. An empty key keeps the
+// page order.
+func (pa *ProjectsAndActivities) SortProjects(key string) error {
+	var less func(a, b Project) bool
+	switch key {
+	case "":
+		return nil
+	case SortByID:
+		less = func(a, b Project) bool { return lessID(a.ID, b.ID) }
+	case SortByName:
+		less = func(a, b Project) bool {
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+			return lessID(a.ID, b.ID)
+		}
+	default:
+		return fmt.Errorf("unknown sort key %q (use %q or %q)", key, SortByID, SortByName)
 	}
 
-	return result
+	sort.SliceStable(pa.Projects, func(i, j int) bool {
+		return less(pa.Projects[i], pa.Projects[j])
+	})
+	return nil
+}
+
+// lessID compares two IDs numerically if possible, lexically otherwise.
+func lessID(a, b string) bool {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	return a < b
 }
 
-// parseJSArrays extracts projects and activities from JavaScript in HTML.
+var (
+	projectSelectName = regexp.MustCompile(`^project\d*$`)
+	numberPrefix      = regexp.MustCompile(`^(\d+[\.\)]\s+)(.*)`)
+	wbsMarker         = regexp.MustCompile(`(.+?)\s*<<([^>]+)>>`)
+	projectNamePrefix = regexp.MustCompile(`([^<]+)\s*<<`)
+)
+
+// parseJSArrays extracts projects from the project dropdowns and activities
+// from the act.append(...) calls in the page's JavaScript.
 func parseJSArrays(htmlContent string) ([]Project, []Activity) {
-	projects := make([]Project, 0)
+	projects := parseProjectOptions(htmlContent)
 	activities := make([]Activity, 0)
-	projectMap := make(map[string]string) // id -> name
-
-	// Pattern 1: Standard dropdown: <option value="PROJECT_ID">PROJECT_NAME</option>
-	projectPattern1 := regexp.MustCompile(`<option value="(\d+)">([^<]+)</option>`)
-	matches1 := projectPattern1.FindAllStringSubmatch(htmlContent, -1)
 
-	// Pattern 2: Dropdown with attributes: <option value="PROJECT_ID" ...>PROJECT_NAME</option>
-	projectPattern2 := regexp.MustCompile(`<option[^>]*\svalue="(\d+)"[^>]*>([^<]+)</option>`)
-	matches2 := projectPattern2.FindAllStringSubmatch(htmlContent, -1)
+	projectMap := make(map[string]string) // id -> name
+	for _, p := range projects {
+		projectMap[p.ID] = p.Name
+	}
 
-	// Combine all project matches
-	seen := make(map[string]bool)
-	for _, match := range append(matches1, matches2...) {
-		if len(match) >= 3 {
-			value := match[1]
-			name := strings.TrimSpace(match[2])
+	calls := parseActAppendCalls(htmlContent)
 
-			// Skip default options and special projects
-			if strings.Contains(strings.ToLower(name), "select project") || value == "--" || name == "" {
+	// If no projects found from dropdown, extract from activities
+	if len(projects) == 0 {
+		for _, args := range calls {
+			projectID := args[0]
+			if _, ok := projectMap[projectID]; ok {
 				continue
 			}
 
-			key := value + "_" + name
-			if !seen[key] {
-				seen[key] = true
-				projectMap[value] = name
-				projects = append(projects, Project{
-					ID:   value,
-					Name: name,
-				})
+			projectName := strings.TrimSpace(args[1])
+			// Extract prefix as project name if has <<x>> format
+			if nameMatch := projectNamePrefix.FindStringSubmatch(projectName); len(nameMatch) >= 2 {
+				projectName = strings.TrimSpace(nameMatch[1])
 			}
+
+			projectMap[projectID] = projectName
+			projects = append(projects, Project{
+				ID:   projectID,
+				Name: projectName,
+			})
 		}
 	}
 
-	// Extract activities from JavaScript: act.append('PROJECT_ID','ACTIVITY_NAME','IS_BOTTOM','UID','PROGRESS')
-	activityPattern := regexp.MustCompile(`act\.append\('(\d+)',\s*'([^']+)',\s*'([^']+)',\s*'([^']+)',\s*'([^']+)'\)`)
-	activityMatches := activityPattern.FindAllStringSubmatch(htmlContent, -1)
-
-	// If no projects found from dropdown, extract from activities
-	if len(projects) == 0 {
-		projectIDs := make(map[string]bool)
-		for _, match := range activityMatches {
-			if len(match) >= 2 {
-				projectIDs[match[1]] = true
-			}
+	// Process all activities
+	for _, args := range calls {
+		projectID := args[0]
+		activityName := args[1]
+		isBottom := strings.ToLower(strings.TrimSpace(args[2])) == "true"
+		uid := args[3]
+		progress := args[4]
+
+		// Ensure we have this project
+		if _, ok := projectMap[projectID]; !ok {
+			projectMap[projectID] = "Project " + projectID
+			projects = append(projects, Project{
+				ID:   projectID,
+				Name: projectMap[projectID],
+			})
 		}
 
-		for projectID := range projectIDs {
-			for _, match := range activityMatches {
-				if len(match) >= 2 && match[1] == projectID {
-					activityName := strings.TrimSpace(match[2])
-					projectName := activityName
+		// Process activity name for hierarchy info
+		indentLevel := 0
+		cleanedName := activityName
 
-					// Extract prefix as project name if has <<x>> format
-					nameMatch := regexp.MustCompile(`([^<]+)\s*<<`).FindStringSubmatch(activityName)
-					if len(nameMatch) >= 2 {
-						projectName = strings.TrimSpace(nameMatch[1])
-					}
+		// Check for leading spaces
+		if trimmed := strings.TrimLeft(activityName, " \t\u00a0\u3000"); len(trimmed) != len(activityName) {
+			indentLevel = len([]rune(activityName)) - len([]rune(trimmed))
+			cleanedName = strings.TrimSpace(trimmed)
+		}
 
-					projectMap[projectID] = projectName
-					projects = append(projects, Project{
-						ID:   projectID,
-						Name: projectName,
-					})
-					break
-				}
-			}
+		// Remove number prefix like "1. Activity Name"
+		if match := numberPrefix.FindStringSubmatch(cleanedName); len(match) >= 3 {
+			cleanedName = match[2]
+		}
+
+		// Split off <<x.y.z>> WBS markers
+		wbs := ""
+		if match := wbsMarker.FindStringSubmatch(cleanedName); len(match) >= 3 {
+			cleanedName = strings.TrimSpace(match[1])
+			wbs = strings.TrimSpace(match[2])
 		}
+
+		activities = append(activities, Activity{
+			ID:          projectID + "_" + cleanedName + "_" + uid,
+			ProjectID:   projectID,
+			Name:        cleanedName,
+			FullName:    activityName,
+			IsBottom:    isBottom,
+			UID:         uid,
+			Progress:    progress,
+			IndentLevel: indentLevel,
+			WBS:         wbs,
+		})
 	}
 
-	// Process all activities
-	for _, match := range activityMatches {
-		if len(match) >= 6 {
-			projectID := match[1]
-			activityName := match[2]
-			isBottom := strings.ToLower(match[3]) == "true"
-			uid := match[4]
-			progress := match[5]
-
-			// Ensure we have this project
-			if _, ok := projectMap[projectID]; !ok {
-				projectMap[projectID] = "Project " + projectID
-				projects = append(projects, Project{
-					ID:   projectID,
-					Name: projectMap[projectID],
-				})
-			}
+	return projects, activities
+}
 
-			// Process activity name for hierarchy info
-			indentLevel := 0
-			cleanedName := activityName
+// parseProjectOptions extracts projects from the options of the project
+// <select> elements (project, project0, project1, ...). Other dropdowns on
+// the page are ignored. Entities in names are decoded by the HTML parser.
+func parseProjectOptions(htmlContent string) []Project {
+	projects := make([]Project, 0)
 
-			// Check for leading spaces
-			if match := regexp.MustCompile(`^\s+`).FindString(activityName); match != "" {
-				indentLevel = len(match)
-				cleanedName = strings.TrimSpace(activityName)
-			}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return projects
+	}
 
-			// Remove number prefix like "1. Activity Name"
-			if match := regexp.MustCompile(`^(\d+[\.\)]\s+)(.*)`).FindStringSubmatch(cleanedName); len(match) >= 3 {
-				cleanedName = match[2]
-			}
+	seen := make(map[string]bool)
+	doc.Find("select").Each(func(_ int, sel *goquery.Selection) {
+		name, _ := sel.Attr("name")
+		if !projectSelectName.MatchString(name) {
+			return
+		}
 
-			// Remove <<x.y.z>> markers
-			if match := regexp.MustCompile(`(.+)\s*<<[^>]+>>`).FindStringSubmatch(cleanedName); len(match) >= 2 {
-				cleanedName = strings.TrimSpace(match[1])
-			}
+		sel.Find("option").Each(func(_ int, opt *goquery.Selection) {
+			value, ok := opt.Attr("value")
+			value = strings.TrimSpace(value)
+			projectName := strings.TrimSpace(opt.Text())
 
-			activities = append(activities, Activity{
-				ID:          projectID + "_" + cleanedName + "_" + uid,
-				ProjectID:   projectID,
-				Name:        cleanedName,
-				FullName:    activityName,
-				IsBottom:    isBottom,
-				UID:         uid,
-				Progress:    progress,
-				IndentLevel: indentLevel,
+			// Skip default options and special projects
+			if !ok || value == "" || value == "--" || projectName == "" ||
+				strings.Contains(strings.ToLower(projectName), "select project") {
+				return
+			}
+			if seen[value] {
+				return
+			}
+			seen[value] = true
+			projects = append(projects, Project{
+				ID:   value,
+				Name: projectName,
 			})
-		}
-	}
+		})
+	})
 
-	return projects, activities
+	return projects
 }
 
 // ParseWeekTimecard parses HTML content to extract week timecard data.
@@ -267,10 +328,11 @@ func ParseWeekTimecard(htmlContent, weekStartDate string) *WeekTimecard {
 		}
 
 		// Get activity data
-		var activityData string
+		var activityData, activityName string
 		activitySelect := row.Find("select[name='activity" + idxStr + "']")
 		activitySelect.Find("option[selected]").Each(func(_ int, opt *goquery.Selection) {
 			activityData, _ = opt.Attr("value")
+			activityName = strings.TrimSpace(opt.Text())
 		})
 
 		// Get progress
@@ -285,7 +347,6 @@ func ParseWeekTimecard(htmlContent, weekStartDate string) *WeekTimecard {
 		days := make([]DayEntry, 7)
 		for dayIdx := 0; dayIdx < 7; dayIdx++ {
 			dayEntry := DayEntry{
-				Hours:    "",
 				Note:     "",
 				Progress: 0,
 			}
@@ -293,10 +354,10 @@ func ParseWeekTimecard(htmlContent, weekStartDate string) *WeekTimecard {
 			// Get hours
 			hourInput := row.Find("input[name='record" + strconv.Itoa(idx) + "_" + strconv.Itoa(dayIdx) + "']")
 			if hourInput.Length() > 0 {
-				if val, _ := hourInput.Attr("value"); val != "" && strings.TrimSpace(val) != "" {
-					if hours, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+				if val, _ := hourInput.Attr("value"); strings.TrimSpace(val) != "" {
+					if hours, err := ParseHours(val); err == nil {
 						dayEntry.Hours = hours
-						result.DailyTotals[dayIdx] += hours
+						result.DailyTotals[dayIdx] += hours.Float()
 					}
 				}
 			}
@@ -322,6 +383,7 @@ func ParseWeekTimecard(htmlContent, weekStartDate string) *WeekTimecard {
 			ProjectID:    projectID,
 			ProjectName:  projectName,
 			ActivityData: activityData,
+			ActivityName: activityName,
 			Progress:     progress,
 			Days:         days,
 		})
@@ -341,5 +403,7 @@ func ParseWeekTimecard(htmlContent, weekStartDate string) *WeekTimecard {
 		}
 	})
 
+	parseWeekMeta(doc, result)
+
 	return result
 }
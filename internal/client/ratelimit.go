@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRetries is how often a GET is retried when the server answers
+// 429 Too Many Requests or 503 Service Unavailable.
+const maxRetries = 3
+
+// throttle spaces out the start of requests by a minimum interval,
+// shared by all goroutines using the client.
+type throttle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newThrottle(interval time.Duration) *throttle {
+	return &throttle{interval: interval}
+}
+
+// wait blocks until the next request may start or ctx is done.
+func (t *throttle) wait(ctx context.Context) error {
+	if t == nil || t.interval <= 0 {
+		return ctx.Err()
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	at := t.next
+	if at.Before(now) {
+		at = now
+	}
+	t.next = at.Add(t.interval)
+	t.mu.Unlock()
+
+	return sleep(ctx, time.Until(at))
+}
+
+// getWithRetry sends a GET request once the throttle allows it. When the
+// server asks to slow down, the request is retried after the delay given
+// by Retry-After, or an increasing backoff if there is none.
+func (c *Client) getWithRetry(ctx context.Context, rawURL string) (*http.Response, error) {
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		if err := c.throttle.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.setCommonHeaders(req)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if attempt == maxRetries {
+			return nil, fmt.Errorf("server is busy: %s", resp.Status)
+		}
+
+		delay := backoff
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			delay = time.Duration(secs) * time.Second
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
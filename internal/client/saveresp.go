@@ -0,0 +1,205 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SaveError is returned when weekinfo_deal.jsp rejects a save or submit.
+// Row and Day point at the offending entry (index into the posted
+// entries) and weekday column when the message identifies them, and are
+// -1 otherwise.
+type SaveError struct {
+	Action    string   // "save" or "submit"
+	Messages  []string // messages shown by the server
+	Row       int
+	Day       int
+	Date      string // date of Day's column, if known
+	ProjectID string // project of Row, if known
+}
+
+// Error implements the error interface.
+func (e *SaveError) Error() string {
+	msg := fmt.Sprintf("server indicated %s failure", e.Action)
+	if len(e.Messages) > 0 {
+		msg = fmt.Sprintf("%s rejected by server: %s", e.Action, strings.Join(e.Messages, "; "))
+	}
+
+	where := make([]string, 0, 2)
+	if e.Row >= 0 {
+		row := fmt.Sprintf("row %d", e.Row+1)
+		if e.ProjectID != "" {
+			row += " (project " + e.ProjectID + ")"
+		}
+		where = append(where, row)
+	}
+	if e.Date != "" {
+		where = append(where, e.Date)
+	} else if e.Day >= 0 {
+		where = append(where, fmt.Sprintf("day %d", e.Day+1))
+	}
+	if len(where) > 0 {
+		msg += " [" + strings.Join(where, ", ") + "]"
+	}
+	return msg
+}
+
+var (
+	fieldRef = regexp.MustCompile(`\b(?:record|note|progress|project|activity)(\d+)(?:_(\d))?\b`)
+	rowRef   = regexp.MustCompile(`(?i)(?:\brow\s*#?\s*(\d+)|第\s*(\d+)\s*(?:列|行|筆))`)
+	// dayRefs are the ways messages name a weekday, indexed by time.Weekday
+	dayRefs = [][]string{
+		{"週日", "星期日", "sunday", "sun"},
+		{"週一", "星期一", "monday", "mon"},
+		{"週二", "星期二", "tuesday", "tue"},
+		{"週三", "星期三", "wednesday", "wed"},
+		{"週四", "星期四", "thursday", "thu"},
+		{"週五", "星期五", "friday", "fri"},
+		{"週六", "星期六", "saturday", "sat"},
+	}
+	failureWords = []string{"error", "fail", "invalid", "exceed", "must", "not allowed", "錯誤", "失敗", "不可", "超過", "必須", "無效", "不得"}
+	successWords = []string{"success", "saved", "成功", "完成"}
+)
+
+// parseSaveResponse inspects the response of weekinfo_deal.jsp and returns
+// a *SaveError if the server rejected the post, or nil on success.
+func parseSaveResponse(body, weekStartDate string, entries []SaveEntry, action weekAction) error {
+	messages := make([]string, 0)
+	for _, args := range findJSCalls(body, "alert(") {
+		if len(args) > 0 {
+			messages = append(messages, args[0])
+		}
+	}
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(body)); err == nil {
+		doc.Find("[class*='err'], [id*='err'], [class*='msg'], [id*='msg'], [class*='message'], [id*='message'], font[color='red']").Each(func(_ int, s *goquery.Selection) {
+			messages = append(messages, s.Text())
+		})
+	}
+
+	// Keep distinct, non-empty messages that aren't success notices
+	failures := make([]string, 0)
+	seen := make(map[string]bool)
+	failed := false
+	for _, m := range messages {
+		m = strings.Join(strings.Fields(m), " ")
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		if containsAny(m, failureWords) {
+			failed = true
+		} else if containsAny(m, successWords) {
+			continue
+		}
+		failures = append(failures, m)
+	}
+
+	bodyLower := strings.ToLower(body)
+	if !failed && !strings.Contains(bodyLower, "error") && !strings.Contains(bodyLower, "failed") {
+		return nil
+	}
+
+	saveErr := &SaveError{
+		Action:   action.verb,
+		Messages: failures,
+		Row:      -1,
+		Day:      -1,
+	}
+	locateSaveError(saveErr, weekStartDate)
+	if saveErr.Row >= 0 && saveErr.Row < len(entries) {
+		saveErr.ProjectID = entries[saveErr.Row].ProjectID
+	}
+	return saveErr
+}
+
+// locateSaveError fills Row, Day and Date from field names ("record3_2"),
+// row numbers, weekday names or dates mentioned in the messages.
+func locateSaveError(e *SaveError, weekStartDate string) {
+	text := strings.Join(e.Messages, " ")
+
+	if m := fieldRef.FindStringSubmatch(text); m != nil {
+		e.Row, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			e.Day, _ = strconv.Atoi(m[2])
+		}
+	} else if m := rowRef.FindStringSubmatch(text); m != nil {
+		n := m[1]
+		if n == "" {
+			n = m[2]
+		}
+		if row, err := strconv.Atoi(n); err == nil && row > 0 {
+			e.Row = row - 1 // messages count rows from 1
+		}
+	}
+
+	start, err := time.Parse("2006-01-02", weekStartDate)
+	if err != nil {
+		return
+	}
+	if e.Day < 0 {
+		e.Day = locateDay(text, start)
+	}
+	if e.Day >= 0 {
+		e.Date = columnDate(weekStartDate, e.Day)
+	}
+}
+
+// locateDay returns the day column named in text by weekday or date, or
+// -1 if none is.
+func locateDay(text string, start time.Time) int {
+	lower := strings.ToLower(text)
+	for wd, names := range dayRefs {
+		for _, name := range names {
+			if containsWord(lower, name) {
+				return (wd - int(start.Weekday()) + 7) % 7
+			}
+		}
+	}
+
+	for dayIdx := 0; dayIdx < 7; dayIdx++ {
+		d := start.AddDate(0, 0, dayIdx)
+		for _, layout := range []string{"2006-01-02", "2006/01/02", "2006/1/2", "01/02", "1/2"} {
+			if containsWord(text, d.Format(layout)) {
+				return dayIdx
+			}
+		}
+	}
+	return -1
+}
+
+// containsAny reports whether s contains any of the words, ignoring case.
+func containsAny(s string, words []string) bool {
+	s = strings.ToLower(s)
+	for _, w := range words {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWord reports whether s contains word not directly surrounded by
+// ASCII letters or digits, so "mon" does not match "month".
+func containsWord(s, word string) bool {
+	for from := 0; ; {
+		i := strings.Index(s[from:], word)
+		if i < 0 {
+			return false
+		}
+		i += from
+		end := i + len(word)
+		if (i == 0 || !isAlnum(s[i-1])) && (end == len(s) || !isAlnum(s[end])) {
+			return true
+		}
+		from = i + 1
+	}
+}
+
+func isAlnum(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSaveResponse(t *testing.T) {
+	entries := []SaveEntry{{ProjectID: "12345"}, {ProjectID: "67890"}, {ProjectID: "11111"}}
+	tests := []struct {
+		name string
+		body string
+		want string // error text, or "" for success
+		row  int
+		day  int
+	}{
+		{"plain success", `<html><body><script>alert('Saved successfully');location.href='weekinfo.jsp';</script></body></html>`, "", -1, -1},
+		{"success notice in a message element", `<div class="msg">儲存成功</div>`, "", -1, -1},
+		{"empty page", ``, "", -1, -1},
+		{"field reference", `<script>alert('Invalid value in record1_3');history.back();</script>`,
+			"save rejected by server: Invalid value in record1_3 [row 2 (project 67890), 2025-01-16 (Thu)]", 1, 3},
+		{"chinese row number", `<script>alert("第 3 列: 工時超過上限");</script>`,
+			"save rejected by server: 第 3 列: 工時超過上限 [row 3 (project 11111)]", 2, -1},
+		{"row and weekday", `<font color="red">Row 1: hours on Friday must not exceed 24</font>`,
+			"save rejected by server: Row 1: hours on Friday must not exceed 24 [row 1 (project 12345), 2025-01-17 (Fri)]", 0, 4},
+		{"chinese weekday", `<span id="errMsg">週日 工時錯誤</span>`,
+			"save rejected by server: 週日 工時錯誤 [2025-01-19 (Sun)]", -1, 6},
+		{"date", `<script>alert('Error: 2025/01/14 is closed');</script>`,
+			"save rejected by server: Error: 2025/01/14 is closed [2025-01-14 (Tue)]", -1, 1},
+		{"month is not monday", `<script>alert('Error: the month is closed');</script>`,
+			"save rejected by server: Error: the month is closed", -1, -1},
+		{"error without message", `<html><body>Internal error</body></html>`, "server indicated save failure", -1, -1},
+		{"messages deduplicated", `<div class="err">Invalid hours</div><div id="errbox"><div class="err">Invalid hours</div></div>`,
+			"save rejected by server: Invalid hours", -1, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseSaveResponse(tt.body, "2025-01-13", entries, actionSave)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("parseSaveResponse = %v, want success", err)
+				}
+				return
+			}
+			var saveErr *SaveError
+			if !errors.As(err, &saveErr) {
+				t.Fatalf("parseSaveResponse = %v, want a *SaveError", err)
+			}
+			if saveErr.Error() != tt.want || saveErr.Row != tt.row || saveErr.Day != tt.day {
+				t.Errorf("parseSaveResponse = %q (row %d, day %d), want %q (row %d, day %d)",
+					saveErr.Error(), saveErr.Row, saveErr.Day, tt.want, tt.row, tt.day)
+			}
+		})
+	}
+}
+
+func TestParseSaveResponseSubmit(t *testing.T) {
+	submit := weekAction{name: "submit2", value: "送出", verb: "submit"}
+	err := parseSaveResponse(`<script>alert('送出失敗');</script>`, "2025-01-13", nil, submit)
+	if err == nil || err.Error() != "submit rejected by server: 送出失敗" {
+		t.Errorf("parseSaveResponse = %v, want a submit failure", err)
+	}
+}
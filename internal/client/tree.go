@@ -0,0 +1,88 @@
+package client
+
+import "strings"
+
+// ActivityNode is an activity together with its child activities.
+type ActivityNode struct {
+	Activity
+	Children []*ActivityNode `json:"children,omitempty"`
+}
+
+// ProjectTree is a project whose activities are arranged as a WBS tree.
+type ProjectTree struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Activities []*ActivityNode `json:"activities"`
+}
+
+// Tree returns the project with its activities arranged as a tree.
+func (p Project) Tree() ProjectTree {
+	return ProjectTree{
+		ID:         p.ID,
+		Name:       p.Name,
+		Activities: BuildActivityTree(p.Activities),
+	}
+}
+
+// BuildActivityTree arranges activities into a parent/child tree. An
+// activity whose WBS code is "1.2.3" is placed under the activity coded
+// "1.2" when one exists. Otherwise the nesting follows page order: an
+// activity becomes a child of the nearest preceding activity at a
+// shallower level (WBS depth, or indent when there is no WBS code).
+func BuildActivityTree(activities []Activity) []*ActivityNode {
+	roots := make([]*ActivityNode, 0)
+	byWBS := make(map[string]*ActivityNode)
+
+	type frame struct {
+		node  *ActivityNode
+		level int
+	}
+	stack := make([]frame, 0)
+
+	for _, act := range activities {
+		node := &ActivityNode{Activity: act}
+		level := activityLevel(act)
+
+		for len(stack) > 0 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+
+		var parent *ActivityNode
+		if p, ok := byWBS[parentWBS(act.WBS)]; ok {
+			parent = p
+		} else if len(stack) > 0 {
+			parent = stack[len(stack)-1].node
+		}
+
+		if parent != nil {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+
+		if act.WBS != "" {
+			byWBS[act.WBS] = node
+		}
+		stack = append(stack, frame{node: node, level: level})
+	}
+
+	return roots
+}
+
+// activityLevel returns the nesting level of an activity: the number of
+// WBS segments if it has a code, its indent otherwise.
+func activityLevel(act Activity) int {
+	if act.WBS != "" {
+		return strings.Count(act.WBS, ".") + 1
+	}
+	return act.IndentLevel
+}
+
+// parentWBS returns the WBS code of the parent ("1.2" for "1.2.3"), or ""
+// for top-level codes.
+func parentWBS(wbs string) string {
+	if i := strings.LastIndex(wbs, "."); i > 0 {
+		return wbs[:i]
+	}
+	return ""
+}
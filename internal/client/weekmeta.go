@@ -0,0 +1,474 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// WeekStatus is the approval state of a week timecard.
+type WeekStatus string
+
+// Week statuses reported by TCRS.
+const (
+	WeekStatusUnknown   WeekStatus = ""
+	WeekStatusDraft     WeekStatus = "draft"
+	WeekStatusSubmitted WeekStatus = "submitted"
+	WeekStatusApproved  WeekStatus = "approved"
+	WeekStatusRejected  WeekStatus = "rejected"
+)
+
+// statusKeywords maps words of the page's status text to a status.
+// Latin keywords must match whole words, so a "Save" button or a
+// "return" in a script does not count; CJK keywords match as substrings.
+// Checked in order, so "not submitted" hits draft before submitted.
+var statusKeywords = []struct {
+	status   WeekStatus
+	keywords []string
+}{
+	{WeekStatusRejected, []string{"rejected", "returned", "退回", "駁回", "退件"}},
+	{WeekStatusApproved, []string{"approved", "已核准", "核准", "已審核"}},
+	{WeekStatusDraft, []string{"draft", "saved", "not submitted", "unsubmitted", "未送出", "暫存", "已儲存"}},
+	{WeekStatusSubmitted, []string{"submitted", "pending", "pending approval", "已送出", "送審", "審核中", "待審"}},
+}
+
+var (
+	statusLabel  = regexp.MustCompile(`(?i)(?:status|狀態)\s*[:：]\s*([^\s<|]+(?:\s[a-zA-Z]+)?)`)
+	commentLabel = regexp.MustCompile(`(?i)(?:approver\s*comments?|審核意見|主管意見|退回原因)\s*[:：]\s*(.+)`)
+	fullDate     = regexp.MustCompile(`(\d{4})[/\-.](\d{1,2})[/\-.](\d{1,2})`)
+	// shortDate may follow a weekday name directly ("Mon<br>01/13")
+	shortDate = regexp.MustCompile(`(?:^|[^\d/])(\d{1,2})/(\d{1,2})(?:$|[^\d/])`)
+)
+
+// parseWeekMeta fills the status, lock state, approver comments and
+// column dates of a week timecard from the page.
+func parseWeekMeta(doc *goquery.Document, result *WeekTimecard) {
+	form := weekForm(doc)
+	result.Dates = parseColumnDates(doc, result.WeekStartDate)
+	result.Status = parseWeekStatus(form)
+	result.ApproverComments = parseApproverComments(form)
+	result.Locked = isWeekLocked(form, result.Status)
+	result.submitButton = findSubmitButton(doc)
+	result.RowCapacity = parseRowCapacity(doc)
+}
+
+// weekForm returns the form holding the timecard table, or the page body
+// if there is none.
+func weekForm(doc *goquery.Document) *goquery.Selection {
+	form := doc.Find("form").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return s.Find("table.timecard_table, input[name^='record']").Length() > 0
+	}).First()
+	if form.Length() == 0 {
+		return doc.Find("body")
+	}
+	return form
+}
+
+// visibleText returns the text of s without scripts, styles, option
+// lists and button labels.
+func visibleText(s *goquery.Selection) string {
+	clone := s.Clone()
+	clone.Find("script, style, noscript, select, button").Remove()
+	return clone.Text()
+}
+
+// parseRowCapacity returns the number of project rows in the week form,
+// judged by the highest projectN select, or DefaultRowCapacity if none.
+func parseRowCapacity(doc *goquery.Document) int {
+	capacity := 0
+	doc.Find("select[name^='project']").Each(func(_ int, s *goquery.Selection) {
+		name, _ := s.Attr("name")
+		if idx, err := strconv.Atoi(strings.TrimPrefix(name, "project")); err == nil && idx+1 > capacity {
+			capacity = idx + 1
+		}
+	})
+	if capacity == 0 {
+		return DefaultRowCapacity
+	}
+	return capacity
+}
+
+// parseColumnDates returns the dates in the timecard header row as
+// YYYY-MM-DD strings, or nil if the header does not show seven dates.
+// Only header rows (thead, or the first row with th cells) are read, so
+// dates written in a note cannot be taken for the columns. Short MM/DD
+// dates take their year from the requested week.
+func parseColumnDates(doc *goquery.Document, weekStartDate string) []string {
+	year := time.Now().Year()
+	if t, err := time.Parse("2006-01-02", weekStartDate); err == nil {
+		year = t.Year()
+	}
+
+	header := doc.Find("table.timecard_table thead tr")
+	if header.Length() == 0 {
+		header = doc.Find("table.timecard_table tr").FilterFunction(func(_ int, row *goquery.Selection) bool {
+			return row.Find("th").Length() > 0
+		}).First()
+	}
+
+	var dates []string
+	header.EachWithBreak(func(_ int, row *goquery.Selection) bool {
+		found := make([]string, 0, 7)
+		row.Find("th, td").Each(func(_ int, cell *goquery.Selection) {
+			if d := parseHeaderDate(cell.Text(), year); d != "" {
+				found = append(found, d)
+			}
+		})
+		if len(found) >= 7 {
+			dates = fixYearWrap(found[:7])
+			return false
+		}
+		return true
+	})
+
+	return dates
+}
+
+// parseHeaderDate extracts a date from a header cell.
+func parseHeaderDate(text string, year int) string {
+	if m := fullDate.FindStringSubmatch(text); m != nil {
+		y, _ := strconv.Atoi(m[1])
+		mo, _ := strconv.Atoi(m[2])
+		d, _ := strconv.Atoi(m[3])
+		return fmt.Sprintf("%04d-%02d-%02d", y, mo, d)
+	}
+	if m := shortDate.FindStringSubmatch(text); m != nil {
+		mo, _ := strconv.Atoi(m[1])
+		d, _ := strconv.Atoi(m[2])
+		if mo < 1 || mo > 12 || d < 1 || d > 31 {
+			return ""
+		}
+		return fmt.Sprintf("%04d-%02d-%02d", year, mo, d)
+	}
+	return ""
+}
+
+// fixYearWrap adjusts short dates of a week spanning New Year, where the
+// columns read 12/30, 12/31, 01/01 ... but all got the same year.
+func fixYearWrap(dates []string) []string {
+	for i := 1; i < len(dates); i++ {
+		if dates[i] < dates[i-1] {
+			t, err := time.Parse("2006-01-02", dates[i])
+			if err != nil {
+				continue
+			}
+			dates[i] = t.AddDate(1, 0, 0).Format("2006-01-02")
+		}
+	}
+	return dates
+}
+
+// parseWeekStatus determines the week status from a status element or a
+// "Status: ..." label in the week form.
+func parseWeekStatus(form *goquery.Selection) WeekStatus {
+	texts := make([]string, 0)
+	form.Find("[id*='status' i], [class*='status' i], [name*='status' i]").Not("script, style").Each(func(_ int, s *goquery.Selection) {
+		if v, ok := s.Attr("value"); ok {
+			texts = append(texts, v)
+		}
+		texts = append(texts, visibleText(s))
+	})
+	for _, m := range statusLabel.FindAllStringSubmatch(visibleText(form), -1) {
+		texts = append(texts, m[1])
+	}
+
+	for _, text := range texts {
+		if status := matchStatus(text); status != WeekStatusUnknown {
+			return status
+		}
+	}
+	return WeekStatusUnknown
+}
+
+// matchStatus maps a status text to a WeekStatus.
+func matchStatus(text string) WeekStatus {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return WeekStatusUnknown
+	}
+	words := " " + strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}), " ") + " "
+	for _, sk := range statusKeywords {
+		for _, kw := range sk.keywords {
+			if isASCII(kw) && strings.Contains(words, " "+kw+" ") ||
+				!isASCII(kw) && strings.Contains(text, kw) {
+				return sk.status
+			}
+		}
+	}
+	return WeekStatusUnknown
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// parseApproverComments collects approver comments from comment elements
+// or "Approver comment: ..." labels in the week form.
+func parseApproverComments(form *goquery.Selection) []string {
+	comments := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(text string) {
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" || seen[text] {
+			return
+		}
+		seen[text] = true
+		comments = append(comments, text)
+	}
+
+	form.Find("[id*='comment' i], [class*='comment' i], textarea[name*='comment' i]").Not("script, style").Each(func(_ int, s *goquery.Selection) {
+		add(visibleText(s))
+	})
+	if len(comments) == 0 {
+		for _, m := range commentLabel.FindAllStringSubmatch(visibleText(form), -1) {
+			add(m[1])
+		}
+	}
+
+	if len(comments) == 0 {
+		return nil
+	}
+	return comments
+}
+
+// isWeekLocked reports whether the week can no longer be edited: it has
+// been submitted or approved, the save button is disabled, or every hour
+// field is read-only. Without such a sign the week counts as editable,
+// so a renamed or script-drawn save button does not lock it.
+func isWeekLocked(form *goquery.Selection, status WeekStatus) bool {
+	if status == WeekStatusSubmitted || status == WeekStatusApproved {
+		return true
+	}
+
+	records := form.Find("input[name^='record']")
+	if records.Length() == 0 {
+		// Not a timecard form at all; nothing to judge by
+		return false
+	}
+
+	save := form.Find("input[name='save2'], button[name='save2']")
+	if save.Length() > 0 && isDisabled(save) {
+		return true
+	}
+
+	editable := false
+	records.EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		if !isDisabled(s) {
+			editable = true
+			return false
+		}
+		return true
+	})
+	return !editable
+}
+
+// isDisabled reports whether a form element is disabled or read-only.
+func isDisabled(s *goquery.Selection) bool {
+	_, disabled := s.Attr("disabled")
+	_, readonly := s.Attr("readonly")
+	return disabled || readonly
+}
+
+// findSubmitButton looks for the send-for-approval button in the week form.
+func findSubmitButton(doc *goquery.Document) weekAction {
+	var action weekAction
+	doc.Find("input[type='submit'], input[type='button'], button").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		name, _ := s.Attr("name")
+		if name == "" || name == actionSave.name {
+			return true
+		}
+		value, ok := s.Attr("value")
+		if !ok {
+			value = s.Text()
+		}
+		label := strings.ToLower(name + " " + value)
+		for _, kw := range []string{"submit", "送出", "送審", "送工時"} {
+			if strings.Contains(label, kw) {
+				action = weekAction{name: name, value: value, verb: "submit"}
+				return false
+			}
+		}
+		return true
+	})
+	return action
+}
+
+// checkWritable returns ErrWeekLocked if the week cannot be changed.
+func (tc *WeekTimecard) checkWritable() error {
+	if !tc.Locked {
+		return nil
+	}
+	if tc.Status != WeekStatusUnknown {
+		return fmt.Errorf("%w (status: %s)", ErrWeekLocked, tc.Status)
+	}
+	return ErrWeekLocked
+}
+
+// CheckSave returns the error SaveWeekTimecard would refuse entries
+// with before posting them: ErrWeekLocked, ErrWeekMisaligned or
+// ErrTooManyRows. It lets callers check several weeks before saving any.
+func (tc *WeekTimecard) CheckSave(entries []SaveEntry) error {
+	if err := tc.checkWritable(); err != nil {
+		return err
+	}
+	if err := tc.checkAligned(); err != nil {
+		return err
+	}
+	return checkRowCapacity(CompactEntries(entries), tc.RowCapacity)
+}
+
+// ShortDays returns the column indexes of the working days whose total is
+// below the required hours. Columns are matched to dates by the dates
+// shown on the page, or by the week start date. isWorkday decides which
+// dates are working days; nil means Monday to Friday.
+func (tc *WeekTimecard) ShortDays(required float64, isWorkday func(time.Time) bool) []int {
+	if isWorkday == nil {
+		isWorkday = isWeekday
+	}
+	short := make([]int, 0)
+	for dayIdx := 0; dayIdx < 7; dayIdx++ {
+		d, ok := tc.ColumnDate(dayIdx)
+		if !ok || !isWorkday(d) {
+			continue
+		}
+		total := 0.0
+		if dayIdx < len(tc.DailyTotals) {
+			total = tc.DailyTotals[dayIdx]
+		}
+		if total < required {
+			short = append(short, dayIdx)
+		}
+	}
+	return short
+}
+
+// MissingDay is a working day with fewer hours than expected.
+type MissingDay struct {
+	Date     string  `json:"date"`
+	Hours    float64 `json:"hours"`
+	Expected float64 `json:"expected"`
+}
+
+// MissingDays returns the working days between from and to (inclusive)
+// whose total is below expected, in date order. isWorkday decides which
+// dates are working days, as for ShortDays.
+func (tc *WeekTimecard) MissingDays(expected float64, from, to time.Time, isWorkday func(time.Time) bool) []MissingDay {
+	missing := make([]MissingDay, 0)
+	for _, dayIdx := range tc.ShortDays(expected, isWorkday) {
+		d, _ := tc.ColumnDate(dayIdx)
+		if d.Before(from) || d.After(to) {
+			continue
+		}
+		total := 0.0
+		if dayIdx < len(tc.DailyTotals) {
+			total = tc.DailyTotals[dayIdx]
+		}
+		missing = append(missing, MissingDay{
+			Date:     d.Format("2006-01-02"),
+			Hours:    total,
+			Expected: expected,
+		})
+	}
+	return missing
+}
+
+// isWeekday reports whether t falls on Monday to Friday.
+func isWeekday(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// ColumnDate returns the date of a day column, preferring the date shown
+// on the page over the requested week start date.
+func (tc *WeekTimecard) ColumnDate(dayIdx int) (time.Time, bool) {
+	if dayIdx < len(tc.Dates) {
+		if d, err := time.Parse("2006-01-02", tc.Dates[dayIdx]); err == nil {
+			return d, true
+		}
+	}
+	start, err := time.Parse("2006-01-02", tc.WeekStartDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return start.AddDate(0, 0, dayIdx), true
+}
+
+// Misaligned reports whether the page's first column is a different date
+// than the requested week start, meaning hours would land on the wrong
+// days. Pages without header dates are assumed to be aligned.
+func (tc *WeekTimecard) Misaligned() bool {
+	return len(tc.Dates) > 0 && tc.Dates[0] != tc.WeekStartDate
+}
+
+// checkAligned returns ErrWeekMisaligned if the week is misaligned.
+func (tc *WeekTimecard) checkAligned() error {
+	if tc.Misaligned() {
+		return fmt.Errorf("%w: requested %s but the server's week starts %s", ErrWeekMisaligned, tc.WeekStartDate, tc.Dates[0])
+	}
+	return nil
+}
+
+// SaveEntries converts the week's entries back into the shape accepted
+// by SaveWeekTimecard.
+func (tc *WeekTimecard) SaveEntries() []SaveEntry {
+	entries := make([]SaveEntry, 0, len(tc.Entries))
+	for _, e := range tc.Entries {
+		entries = append(entries, e.SaveEntry())
+	}
+	return entries
+}
+
+// ActivityID returns the activity ID taken from the activity data
+// ("true$activity_id$project_id$0"), or "" if no activity is selected.
+func (e WeekEntry) ActivityID() string {
+	activityID := ""
+	if parts := strings.Split(e.ActivityData, "$"); len(parts) >= 2 {
+		activityID = parts[1]
+	}
+	if activityID == "xx" {
+		activityID = ""
+	}
+	return activityID
+}
+
+// SaveEntry converts a week entry into a SaveEntry.
+func (e WeekEntry) SaveEntry() SaveEntry {
+	days := make([]SaveDayEntry, len(e.Days))
+	for i, d := range e.Days {
+		days[i] = SaveDayEntry{
+			Hours:    d.Hours,
+			Note:     d.Note,
+			Progress: d.Progress,
+		}
+	}
+
+	return SaveEntry{
+		ProjectID:  e.ProjectID,
+		ActivityID: e.ActivityID(),
+		Progress:   e.Progress,
+		Days:       days,
+	}
+}
+
+// columnDate describes a day column of the week starting at weekStartDate,
+// e.g. "2025-01-15 (Wed)".
+func columnDate(weekStartDate string, dayIdx int) string {
+	start, err := time.Parse("2006-01-02", weekStartDate)
+	if err != nil {
+		return fmt.Sprintf("day %d", dayIdx+1)
+	}
+	d := start.AddDate(0, 0, dayIdx)
+	return d.Format("2006-01-02 (Mon)")
+}
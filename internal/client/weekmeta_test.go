@@ -0,0 +1,126 @@
+package client
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// weekPage returns a week form page. head is the timecard header row,
+// status and extra go into the form, and disabled is added to the hour
+// fields.
+func weekPage(head, status, extra, disabled string) string {
+	return `<html><body>
+<script>function check(){ if (!ok) return false; alert("approved rows are read-only"); }</script>
+<form name="weekform" method="post" action="weekinfo_deal.jsp">` + status + `
+<table class="timecard_table">` + head + `
+<tr><td><select name="project0"><option value="12345" selected>Alpha</option></select></td>
+<td><select name="activity0"><option value="u1$678" selected>Development</option></select></td>
+<td><input name="record0_0" value="8" ` + disabled + `></td><td><input name="record0_1" value="" ` + disabled + `></td>
+<td><input name="note0_0" value="meeting moved from 01/20 to 01/21"></td></tr>
+<tr><td><select name="project1"><option value="--">--</option></select></td>
+<td><input name="record1_0" value="" ` + disabled + `></td></tr>
+<tr><td><select name="project2"><option value="--">--</option></select></td></tr>
+</table>` + extra + `
+</form></body></html>`
+}
+
+const weekHead = `<thead><tr><th>Project</th><th>Activity</th><th>Mon<br>01/13</th><th>Tue<br>01/14</th><th>Wed<br>01/15</th>` +
+	`<th>Thu<br>01/16</th><th>Fri<br>01/17</th><th>Sat<br>01/18</th><th>Sun<br>01/19</th></tr></thead>`
+
+const saveButtons = `<input type="submit" name="save2" value=" save "><input type="submit" name="submit2" value="送出">`
+
+func TestParseWeekMeta(t *testing.T) {
+	week := []string{"2025-01-13", "2025-01-14", "2025-01-15", "2025-01-16", "2025-01-17", "2025-01-18", "2025-01-19"}
+	tests := []struct {
+		name     string
+		start    string
+		page     string
+		status   WeekStatus
+		locked   bool
+		comments []string
+		dates    []string
+		submit   string
+	}{
+		{"draft", "2025-01-13", weekPage(weekHead, `<span class="status">狀態：暫存</span>`, saveButtons, ""),
+			WeekStatusDraft, false, nil, week, "submit2"},
+		{"no status text", "2025-01-13", weekPage(weekHead, "", saveButtons, ""),
+			WeekStatusUnknown, false, nil, week, "submit2"},
+		{"status label", "2025-01-13", weekPage(weekHead, `<p>Status: Not submitted</p>`, saveButtons, ""),
+			WeekStatusDraft, false, nil, week, "submit2"},
+		{"submitted", "2025-01-13", weekPage(weekHead, `<span id="weekStatus">Submitted</span>`, "", "readonly"),
+			WeekStatusSubmitted, true, nil, week, ""},
+		{"approved", "2025-01-13", weekPage(weekHead, `<input type="hidden" name="status" value="已核准">`, "", ""),
+			WeekStatusApproved, true, nil, week, ""},
+		{"rejected with comments", "2025-01-13", weekPage(weekHead,
+			`<div class="status">退回</div><div class="approver-comment"> Please split
+			 the meetings </div><textarea name="comment2">Add notes</textarea>`, saveButtons, ""),
+			WeekStatusRejected, false, []string{"Please split the meetings", "Add notes"}, week, "submit2"},
+		{"comment label", "2025-01-13", weekPage(weekHead, `<p>審核意見：請補週五工時</p>`, saveButtons, ""),
+			WeekStatusUnknown, false, []string{"請補週五工時"}, week, "submit2"},
+		{"save disabled", "2025-01-13", weekPage(weekHead, "", `<input type="submit" name="save2" value=" save " disabled>`, ""),
+			WeekStatusUnknown, true, nil, week, ""},
+		{"hour fields read-only", "2025-01-13", weekPage(weekHead, "", "", "disabled"),
+			WeekStatusUnknown, true, nil, week, ""},
+		{"no header dates", "2025-01-13", weekPage(`<tr><th>Project</th><th>Mon</th><th>Tue</th></tr>`, "", saveButtons, ""),
+			WeekStatusUnknown, false, nil, nil, "submit2"},
+		{"full dates across new year", "2025-12-29", weekPage(`<tr><th>Project</th><th>2025/12/29</th><th>2025/12/30</th><th>2025/12/31</th>`+
+			`<th>2026/01/01</th><th>2026/01/02</th><th>2026/01/03</th><th>2026/01/04</th></tr>`, "", saveButtons, ""),
+			WeekStatusUnknown, false, nil, []string{"2025-12-29", "2025-12-30", "2025-12-31", "2026-01-01", "2026-01-02", "2026-01-03", "2026-01-04"}, "submit2"},
+		{"short dates across new year", "2025-12-29", weekPage(`<tr><th>Project</th><th>12/29</th><th>12/30</th><th>12/31</th>`+
+			`<th>01/01</th><th>01/02</th><th>01/03</th><th>01/04</th></tr>`, "", saveButtons, ""),
+			WeekStatusUnknown, false, nil, []string{"2025-12-29", "2025-12-30", "2025-12-31", "2026-01-01", "2026-01-02", "2026-01-03", "2026-01-04"}, "submit2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc := &WeekTimecard{WeekStartDate: tt.start}
+			parseWeekMeta(doc, tc)
+			if tc.Status != tt.status || tc.Locked != tt.locked {
+				t.Errorf("status, locked = %q, %v, want %q, %v", tc.Status, tc.Locked, tt.status, tt.locked)
+			}
+			if !reflect.DeepEqual(tc.ApproverComments, tt.comments) {
+				t.Errorf("comments = %q, want %q", tc.ApproverComments, tt.comments)
+			}
+			if !reflect.DeepEqual(tc.Dates, tt.dates) {
+				t.Errorf("dates = %q, want %q", tc.Dates, tt.dates)
+			}
+			if tc.submitButton.name != tt.submit {
+				t.Errorf("submit button = %q, want %q", tc.submitButton.name, tt.submit)
+			}
+			if tc.RowCapacity != 3 {
+				t.Errorf("row capacity = %d, want 3", tc.RowCapacity)
+			}
+		})
+	}
+}
+
+func TestParseWeekTimecard(t *testing.T) {
+	tc := ParseWeekTimecard(weekPage(weekHead, "", saveButtons, ""), "2025-01-13")
+	if len(tc.Entries) != 1 {
+		t.Fatalf("entries = %+v, want one", tc.Entries)
+	}
+	entry := tc.Entries[0].SaveEntry()
+	if entry.ProjectID != "12345" || entry.ActivityID != "678" || entry.Days[0].Hours.String() != "8" || !entry.Days[1].Hours.IsEmpty() {
+		t.Errorf("entry = %+v", entry)
+	}
+	if entry.Days[0].Note != "meeting moved from 01/20 to 01/21" {
+		t.Errorf("note = %q", entry.Days[0].Note)
+	}
+	if tc.DailyTotals[0] != 8 {
+		t.Errorf("daily totals = %v", tc.DailyTotals)
+	}
+	if tc.Misaligned() {
+		t.Errorf("week misaligned with dates %q", tc.Dates)
+	}
+
+	tc = ParseWeekTimecard(weekPage(weekHead, "", saveButtons, ""), "2025-01-12")
+	if !tc.Misaligned() {
+		t.Errorf("week requested from Sunday not misaligned with dates %q", tc.Dates)
+	}
+}
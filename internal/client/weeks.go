@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/tcrs/internal/dates"
+)
+
+// WeekFetchError is a week GetWeeks failed to fetch.
+type WeekFetchError struct {
+	WeekStartDate string
+	Err           error
+}
+
+func (e *WeekFetchError) Error() string {
+	return fmt.Sprintf("week starting %s: %v", e.WeekStartDate, e.Err)
+}
+
+func (e *WeekFetchError) Unwrap() error {
+	return e.Err
+}
+
+// WeeksError collects the weeks GetWeeks failed to fetch, in date order.
+type WeeksError struct {
+	Failed []*WeekFetchError
+}
+
+func (e *WeeksError) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		msgs = append(msgs, f.Error())
+	}
+	return fmt.Sprintf("%d week(s) failed: %s", len(e.Failed), strings.Join(msgs, "; "))
+}
+
+func (e *WeeksError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+// GetWeeks fetches every week overlapping the range from..to (inclusive),
+// using up to cfg.Concurrency requests at once. The weeks are returned in
+// date order. A week that cannot be fetched does not stop the others: the
+// weeks that were fetched are returned along with a *WeeksError listing
+// the ones that failed.
+func (c *Client) GetWeeks(ctx context.Context, from, to time.Time) ([]*WeekTimecard, error) {
+	if !c.loggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	starts := dates.WeekStarts(from, to, c.cfg.WeekStart)
+	weeks := make([]*WeekTimecard, len(starts))
+	errs := make([]error, len(starts))
+
+	workers := c.cfg.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(starts) {
+		workers = len(starts)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				weeks[i], errs[i] = c.getWeekTimecard(ctx, starts[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range starts {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			for j := i; j < len(starts); j++ {
+				errs[j] = ctx.Err()
+			}
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	fetched := make([]*WeekTimecard, 0, len(starts))
+	var failed []*WeekFetchError
+	for i, start := range starts {
+		if errs[i] != nil {
+			failed = append(failed, &WeekFetchError{WeekStartDate: start, Err: errs[i]})
+			continue
+		}
+		fetched = append(fetched, weeks[i])
+	}
+	if len(failed) > 0 {
+		return fetched, &WeeksError{Failed: failed}
+	}
+	return fetched, nil
+}
@@ -5,6 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/dates"
 )
 
 const (
@@ -13,23 +18,57 @@ const (
 	DefaultBaseURL = ""
 	// SessionTimeout is the session timeout in hours.
 	SessionTimeout = 12
+	// DefaultMaxDailyHours is the most hours accepted for a single day.
+	DefaultMaxDailyHours = 24
+	// DefaultHoursStep is the granularity hours must be a multiple of.
+	DefaultHoursStep = 0.25
+	// DefaultLocale is the default language of weekday labels.
+	DefaultLocale = "en"
+	// DefaultExpectedHours is the hours expected on every working day.
+	DefaultExpectedHours = 8
+	// DefaultConcurrency is how many weeks are fetched at once.
+	DefaultConcurrency = 4
+	// DefaultRequestInterval is the least time between starting two requests.
+	DefaultRequestInterval = 100 * time.Millisecond
 )
 
 // Config holds the application configuration.
 type Config struct {
-	BaseURL  string
-	CacheDir string
-	Verbose  bool
-	JSON     bool
+	BaseURL       string
+	CacheDir      string
+	Verbose       bool
+	JSON          bool
+	MaxDailyHours float64 // 0 disables the check
+	HoursStep     float64 // 0 disables the check
+	WeekStart     time.Weekday
+	Locale        string // weekday label language: "en" or "zh"
+	Concurrency   int    // weeks fetched at once by range operations
+	ExpectedHours float64
+	Holidays      []string // YYYY-MM-DD days off in addition to the calendar file
+	ImportColumns []string // field=column pairs mapping imported sheets
+	GitEmail      string   // author of the user's commits, for suggest
+
+	RequestInterval time.Duration // least time between requests, 0 disables
 }
 
 // DefaultConfig returns a Config with default values.
 func DefaultConfig() *Config {
 	return &Config{
-		BaseURL:  getEnvOrDefault("TCRS_BASE_URL", DefaultBaseURL),
-		CacheDir: getEnvOrDefault("TCRS_CACHE_DIR", defaultCacheDir()),
-		Verbose:  false,
-		JSON:     false,
+		BaseURL:       getEnvOrDefault("TCRS_BASE_URL", DefaultBaseURL),
+		CacheDir:      getEnvOrDefault("TCRS_CACHE_DIR", defaultCacheDir()),
+		Verbose:       false,
+		JSON:          false,
+		MaxDailyHours: getEnvFloatOrDefault("TCRS_MAX_DAILY_HOURS", DefaultMaxDailyHours),
+		HoursStep:     getEnvFloatOrDefault("TCRS_HOURS_STEP", DefaultHoursStep),
+		WeekStart:     getEnvWeekdayOrDefault("TCRS_WEEK_START", time.Monday),
+		Locale:        getEnvOrDefault("TCRS_LOCALE", DefaultLocale),
+		Concurrency:   getEnvIntOrDefault("TCRS_CONCURRENCY", DefaultConcurrency),
+		ExpectedHours: getEnvFloatOrDefault("TCRS_EXPECTED_HOURS", DefaultExpectedHours),
+		Holidays:      getEnvListOrDefault("TCRS_HOLIDAYS", nil),
+		ImportColumns: getEnvListOrDefault("TCRS_IMPORT_COLUMNS", nil),
+		GitEmail:      getEnvOrDefault("TCRS_GIT_EMAIL", ""),
+
+		RequestInterval: getEnvDurationOrDefault("TCRS_REQUEST_INTERVAL", DefaultRequestInterval),
 	}
 }
 
@@ -50,6 +89,64 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvFloatOrDefault returns the environment variable parsed as a number,
+// or a default if it is unset or not a number.
+func getEnvFloatOrDefault(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+// getEnvIntOrDefault returns the environment variable parsed as an
+// integer, or a default if it is unset or not an integer.
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDurationOrDefault returns the environment variable parsed as a
+// duration ("250ms", "1s"), or a default if it is unset or invalid.
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// getEnvListOrDefault returns the comma-separated items of the environment
+// variable, or a default if it is unset.
+func getEnvListOrDefault(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvWeekdayOrDefault returns the environment variable parsed as a
+// weekday name, or a default if it is unset or not a weekday.
+func getEnvWeekdayOrDefault(key string, defaultValue time.Weekday) time.Weekday {
+	if wd, ok := dates.ParseWeekday(os.Getenv(key)); ok {
+		return wd
+	}
+	return defaultValue
+}
+
 // EnsureCacheDir creates the cache directory if it doesn't exist.
 func (c *Config) EnsureCacheDir() error {
 	return os.MkdirAll(c.CacheDir, 0700)
@@ -65,6 +162,22 @@ func (c *Config) SessionFile(userID string) string {
 	return filepath.Join(c.CacheDir, userID+".session")
 }
 
+// CalendarFile returns the path to the holiday and leave calendar.
+func (c *Config) CalendarFile() string {
+	return filepath.Join(c.CacheDir, "calendar.yaml")
+}
+
+// MappingFile returns the path to the default rules mapping tracked time
+// to TCRS projects and activities.
+func (c *Config) MappingFile() string {
+	return filepath.Join(c.CacheDir, "mapping.yaml")
+}
+
+// TimerFile returns the path to the running and completed timer intervals.
+func (c *Config) TimerFile() string {
+	return filepath.Join(c.CacheDir, "timer.json")
+}
+
 // ValidateBaseURL checks if the base URL is configured.
 func (c *Config) ValidateBaseURL() error {
 	if c.BaseURL == "" {
@@ -75,4 +188,3 @@ func (c *Config) ValidateBaseURL() error {
 
 // ErrBaseURLNotSet indicates TCRS_BASE_URL is not set.
 var ErrBaseURLNotSet = fmt.Errorf("TCRS_BASE_URL environment variable is not set")
-
@@ -0,0 +1,219 @@
+// Package dates parses the date arguments accepted by the CLI and
+// normalizes them to the start of their week.
+package dates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout is the date format used by TCRS.
+const Layout = "2006-01-02"
+
+var (
+	isoWeek  = regexp.MustCompile(`^(\d{4})-?[wW](\d{1,2})(?:-?([1-7]))?$`)
+	offset   = regexp.MustCompile(`^([+-]\d+)\s*([dw])$`)
+	weekdays = map[string]time.Weekday{
+		"sunday": time.Sunday, "sun": time.Sunday,
+		"monday": time.Monday, "mon": time.Monday,
+		"tuesday": time.Tuesday, "tue": time.Tuesday,
+		"wednesday": time.Wednesday, "wed": time.Wednesday,
+		"thursday": time.Thursday, "thu": time.Thursday,
+		"friday": time.Friday, "fri": time.Friday,
+		"saturday": time.Saturday, "sat": time.Saturday,
+	}
+)
+
+// Parse parses a date argument relative to now. Accepted forms are:
+//
+//	""  or "today", "yesterday", "tomorrow"
+//	2025-01-15, 2025/01/15
+//	2025-W03 (Monday of ISO week 3), 2025-W03-3 (its Wednesday)
+//	this-week, last-week, next-week (today, a week ago, in a week)
+//	-2w, +1w, -3d (offsets from today)
+//	monday, next monday, last friday
+//
+// The result is a date at midnight UTC. A bare weekday name is that day
+// of the current Monday-start week; see ParseOn for other weeks.
+func Parse(s string, now time.Time) (time.Time, error) {
+	return ParseOn(s, now, time.Monday)
+}
+
+// ParseOn is Parse for weeks beginning on first, which decides the week
+// a bare weekday name such as "sun" falls in.
+func ParseOn(s string, now time.Time, first time.Weekday) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	arg := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	arg = strings.NewReplacer("_", "-", " week", "-week").Replace(arg)
+
+	switch arg {
+	case "", "today", "now":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "this-week":
+		return today, nil
+	case "last-week", "prev-week", "previous-week":
+		return today.AddDate(0, 0, -7), nil
+	case "next-week":
+		return today.AddDate(0, 0, 7), nil
+	}
+
+	for _, layout := range []string{Layout, "2006/01/02", "2006-1-2", "2006/1/2"} {
+		if t, err := time.Parse(layout, arg); err == nil {
+			return t, nil
+		}
+	}
+
+	if m := isoWeek.FindStringSubmatch(arg); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		day := 1
+		if m[3] != "" {
+			day, _ = strconv.Atoi(m[3])
+		}
+		return ISOWeekStart(year, week, day)
+	}
+
+	if m := offset.FindStringSubmatch(arg); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "w" {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n), nil
+	}
+
+	if t, ok := parseWeekday(arg, today, first); ok {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, 2025-W03, today, last-week, -2w or next monday)", s)
+}
+
+// ParseWeek parses a date argument like Parse and returns the start of the
+// week containing it, formatted as YYYY-MM-DD. Weeks begin on first.
+func ParseWeek(s string, now time.Time, first time.Weekday) (string, error) {
+	t, err := ParseOn(s, now, first)
+	if err != nil {
+		return "", err
+	}
+	return WeekStartOn(t, first).Format(Layout), nil
+}
+
+// WeekStart returns the Monday of the week containing t.
+func WeekStart(t time.Time) time.Time {
+	return WeekStartOn(t, time.Monday)
+}
+
+// WeekStartOn returns the start of the week containing t, for weeks
+// beginning on first.
+func WeekStartOn(t time.Time, first time.Weekday) time.Time {
+	diff := (int(t.Weekday()) - int(first) + 7) % 7
+	return t.AddDate(0, 0, -diff)
+}
+
+// WeekStarts returns the start of every week, beginning on first, that
+// overlaps the range from..to (inclusive), formatted as YYYY-MM-DD.
+func WeekStarts(from, to time.Time, first time.Weekday) []string {
+	var starts []string
+	for d := WeekStartOn(from, first); !d.After(to); d = d.AddDate(0, 0, 7) {
+		starts = append(starts, d.Format(Layout))
+	}
+	return starts
+}
+
+// ParseWeekday parses an English weekday name or abbreviation.
+func ParseWeekday(name string) (time.Weekday, bool) {
+	wd, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+	return wd, ok
+}
+
+// weekdayLabels are short weekday labels per locale, indexed by
+// time.Weekday.
+var weekdayLabels = map[string][7]string{
+	"en": {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	"zh": {"週日", "週一", "週二", "週三", "週四", "週五", "週六"},
+}
+
+// WeekdayLabels returns the labels of the seven columns of a week
+// beginning on first, in the given locale ("en" or "zh"; "zh-TW" and
+// similar map to "zh"). Unknown locales fall back to English.
+func WeekdayLabels(locale string, first time.Weekday) []string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	table, ok := weekdayLabels[locale]
+	if !ok {
+		table = weekdayLabels["en"]
+	}
+
+	labels := make([]string, 7)
+	for i := range labels {
+		labels[i] = table[(int(first)+i)%7]
+	}
+	return labels
+}
+
+// WeekdayLabel returns the short label of a single weekday in the given
+// locale, as WeekdayLabels does.
+func WeekdayLabel(locale string, wd time.Weekday) string {
+	return WeekdayLabels(locale, wd)[0]
+}
+
+// ISOWeekStart returns the given day (1 = Monday ... 7 = Sunday) of an ISO
+// 8601 week.
+func ISOWeekStart(year, week, day int) (time.Time, error) {
+	if week < 1 || week > 53 {
+		return time.Time{}, fmt.Errorf("invalid ISO week %d", week)
+	}
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	t := WeekStart(jan4).AddDate(0, 0, (week-1)*7+day-1)
+	if y, w := t.ISOWeek(); y != year || w != week {
+		return time.Time{}, fmt.Errorf("%d has no ISO week %d", year, week)
+	}
+	return t, nil
+}
+
+// parseWeekday handles "monday" (that day of the current week, for weeks
+// beginning on first), "next monday" (the next one after today) and
+// "last monday" (the last one before today).
+func parseWeekday(arg string, today time.Time, first time.Weekday) (time.Time, bool) {
+	direction := 0
+	name := arg
+	if rest, ok := strings.CutPrefix(arg, "next "); ok {
+		direction, name = 1, rest
+	} else if rest, ok := strings.CutPrefix(arg, "last "); ok {
+		direction, name = -1, rest
+	}
+
+	wd, ok := weekdays[name]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	switch direction {
+	case 1:
+		diff := (int(wd) - int(today.Weekday()) + 7) % 7
+		if diff == 0 {
+			diff = 7
+		}
+		return today.AddDate(0, 0, diff), true
+	case -1:
+		diff := (int(today.Weekday()) - int(wd) + 7) % 7
+		if diff == 0 {
+			diff = 7
+		}
+		return today.AddDate(0, 0, -diff), true
+	default:
+		idx := (int(wd) - int(first) + 7) % 7
+		return WeekStartOn(today, first).AddDate(0, 0, idx), true
+	}
+}
@@ -0,0 +1,226 @@
+// Package export flattens week timecards into one row per day per
+// project/activity and writes them as CSV, JSON Lines or XLSX.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/xlsx"
+)
+
+// Supported export formats.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatXLSX  = "xlsx"
+)
+
+// Row is the hours of one project/activity on one day.
+type Row struct {
+	Date          string            `json:"date"`
+	WeekStartDate string            `json:"week_start_date"`
+	ProjectID     string            `json:"project_id"`
+	ProjectName   string            `json:"project_name"`
+	ActivityID    string            `json:"activity_id"`
+	ActivityName  string            `json:"activity_name"`
+	Hours         float64           `json:"hours"`
+	Note          string            `json:"note"`
+	Status        client.WeekStatus `json:"status"`
+}
+
+// columns are the header names of CSV and XLSX exports, matching Row.
+var columns = []string{"date", "week_start_date", "project_id", "project_name", "activity_id", "activity_name", "hours", "note", "status"}
+
+// ActivityNames maps a project ID and activity ID to the activity's full
+// name, as listed in the project catalog.
+type ActivityNames map[[2]string]string
+
+// NewActivityNames indexes the activities of a project catalog.
+func NewActivityNames(pa *client.ProjectsAndActivities) ActivityNames {
+	names := make(ActivityNames)
+	if pa == nil {
+		return names
+	}
+	for _, proj := range pa.Projects {
+		for _, act := range proj.Activities {
+			name := act.FullName
+			if name == "" {
+				name = act.Name
+			}
+			names[[2]string{proj.ID, act.ID}] = name
+		}
+	}
+	return names
+}
+
+// Rows flattens weeks into rows for the days between from and to
+// (inclusive) that have hours or a note, in date order. Activity names
+// are looked up in names, falling back to the name shown on the week
+// page.
+func Rows(weeks []*client.WeekTimecard, from, to time.Time, names ActivityNames) []Row {
+	rows := make([]Row, 0)
+	for _, week := range weeks {
+		for _, entry := range week.Entries {
+			activityID := entry.ActivityID()
+			activityName := names[[2]string{entry.ProjectID, activityID}]
+			if activityName == "" {
+				activityName = entry.ActivityName
+			}
+			for dayIdx, day := range entry.Days {
+				if day.Hours.Float() == 0 && day.Note == "" {
+					continue
+				}
+				date, ok := week.ColumnDate(dayIdx)
+				if !ok || date.Before(from) || date.After(to) {
+					continue
+				}
+				rows = append(rows, Row{
+					Date:          date.Format(dates.Layout),
+					WeekStartDate: week.WeekStartDate,
+					ProjectID:     entry.ProjectID,
+					ProjectName:   entry.ProjectName,
+					ActivityID:    activityID,
+					ActivityName:  activityName,
+					Hours:         day.Hours.Float(),
+					Note:          day.Note,
+					Status:        week.Status,
+				})
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Date < rows[j].Date
+	})
+	return rows
+}
+
+// FormatFromPath guesses the export format from a file extension, or
+// returns CSV.
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx":
+		return FormatXLSX
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	default:
+		return FormatCSV
+	}
+}
+
+// CheckFormat returns an error unless format is one Write supports.
+func CheckFormat(format string) error {
+	switch format {
+	case FormatCSV, FormatJSONL, FormatXLSX:
+		return nil
+	default:
+		return fmt.Errorf("unknown export format %q (use csv, xlsx or jsonl)", format)
+	}
+}
+
+// Write writes rows to w in the given format.
+func Write(w io.Writer, rows []Row, format string) error {
+	if err := CheckFormat(format); err != nil {
+		return err
+	}
+	switch format {
+	case FormatJSONL:
+		return writeJSONL(w, rows)
+	case FormatXLSX:
+		return writeXLSX(w, rows)
+	default:
+		return writeCSV(w, rows)
+	}
+}
+
+func (r Row) fields() []string {
+	return []string{
+		r.Date, r.WeekStartDate, r.ProjectID, r.ProjectName, r.ActivityID, r.ActivityName,
+		strconv.FormatFloat(r.Hours, 'f', -1, 64), r.Note, string(r.Status),
+	}
+}
+
+func writeCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	cw.Write(columns)
+	for _, row := range rows {
+		cw.Write(row.fields())
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSONL(w io.Writer, rows []Row) error {
+	enc := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeXLSX writes an "Entries" sheet with one row per day, ready for a
+// pivot table, and a "Totals" sheet with the hours per project/activity.
+func writeXLSX(w io.Writer, rows []Row) error {
+	entries := [][]interface{}{toCells(columns)}
+	for _, row := range rows {
+		entries = append(entries, []interface{}{
+			row.Date, row.WeekStartDate, row.ProjectID, row.ProjectName, row.ActivityID, row.ActivityName,
+			row.Hours, row.Note, string(row.Status),
+		})
+	}
+
+	type totalKey struct{ projectID, activityID string }
+	totals := make(map[totalKey]float64)
+	var order []Row
+	grand := 0.0
+	for _, row := range rows {
+		key := totalKey{row.ProjectID, row.ActivityID}
+		if _, ok := totals[key]; !ok {
+			order = append(order, row)
+		}
+		totals[key] += row.Hours
+		grand += row.Hours
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].ProjectName != order[j].ProjectName {
+			return order[i].ProjectName < order[j].ProjectName
+		}
+		return order[i].ActivityName < order[j].ActivityName
+	})
+
+	totalRows := [][]interface{}{toCells([]string{"project_id", "project_name", "activity_id", "activity_name", "hours", "percent"})}
+	for _, row := range order {
+		hours := totals[totalKey{row.ProjectID, row.ActivityID}]
+		percent := 0.0
+		if grand > 0 {
+			percent = math.Round(hours/grand*1000) / 10
+		}
+		totalRows = append(totalRows, []interface{}{row.ProjectID, row.ProjectName, row.ActivityID, row.ActivityName, hours, percent})
+	}
+	totalRows = append(totalRows, []interface{}{"", "Total", "", "", grand, 100.0})
+
+	return xlsx.Write(w, []xlsx.Sheet{
+		{Name: "Entries", Rows: entries},
+		{Name: "Totals", Rows: totalRows},
+	})
+}
+
+func toCells(values []string) []interface{} {
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		cells[i] = v
+	}
+	return cells
+}
@@ -0,0 +1,224 @@
+// Package ics reads the content lines and events of iCalendar files, for
+// the holiday calendar and for meetings suggested as timecard entries.
+package ics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prop is a content line: NAME;PARAM=VALUE:value.
+type Prop struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Event holds the properties of a VEVENT, in file order.
+type Event []Prop
+
+// Events returns the VEVENTs of an iCalendar file, skipping the
+// properties of components nested in them, such as alarms.
+func Events(data []byte) []Event {
+	var events []Event
+	var event Event
+	inEvent, nested := false, 0
+	for _, line := range Unfold(string(data)) {
+		p := ParseLine(line)
+		switch {
+		case p.Name == "BEGIN" && strings.EqualFold(p.Value, "VEVENT"):
+			event, inEvent, nested = nil, true, 0
+		case !inEvent:
+		case p.Name == "BEGIN":
+			nested++
+		case p.Name == "END" && nested > 0:
+			nested--
+		case p.Name == "END" && strings.EqualFold(p.Value, "VEVENT"):
+			events = append(events, event)
+			inEvent = false
+		case nested == 0:
+			event = append(event, p)
+		}
+	}
+	return events
+}
+
+// Get returns the first property with the given name.
+func (ev Event) Get(name string) (Prop, bool) {
+	for _, p := range ev {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Prop{}, false
+}
+
+// All returns every property with the given name.
+func (ev Event) All(name string) []Prop {
+	var props []Prop
+	for _, p := range ev {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// Value returns the value of the first property with the given name, or
+// "".
+func (ev Event) Value(name string) string {
+	p, _ := ev.Get(name)
+	return p.Value
+}
+
+// Summary returns the unescaped title of the event.
+func (ev Event) Summary() string {
+	return strings.TrimSpace(Unescape(ev.Value("SUMMARY")))
+}
+
+// Unfold splits iCalendar text into logical lines, joining folded
+// continuation lines that start with a space or tab.
+func Unfold(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// ParseLine splits a content line into name, parameters and value.
+// Colons and semicolons inside quoted parameter values are kept.
+func ParseLine(line string) Prop {
+	p := Prop{Params: make(map[string]string)}
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	head := line
+	if colon >= 0 {
+		head, p.Value = line[:colon], strings.TrimSpace(line[colon+1:])
+	}
+	parts := splitParams(head)
+	p.Name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			p.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return p
+}
+
+// splitParams splits the name and parameters of a content line at the
+// semicolons outside quotes.
+func splitParams(head string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, r := range head {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ';' && !quoted:
+			parts = append(parts, head[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, head[start:])
+}
+
+// Unescape undoes iCalendar text escaping.
+func Unescape(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// ParseTime parses a DATE or DATE-TIME value and reports whether it is a
+// date. Times in UTC end in Z; others are in the zone named by TZID, or
+// local time if the zone is unknown (Outlook uses Windows zone names) or
+// not given.
+func ParseTime(p Prop) (time.Time, bool, error) {
+	v := p.Value
+	if p.Params["VALUE"] == "DATE" || len(v) == 8 {
+		t, err := time.ParseInLocation("20060102", v, time.Local)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", v)
+		}
+		return t, true, nil
+	}
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse("20060102T150405Z", v)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid time %q", v)
+		}
+		return t.Local(), false, nil
+	}
+	loc := time.Local
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", v, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q", v)
+	}
+	return t, false, nil
+}
+
+// ParseDuration parses a duration such as PT1H30M or P1D.
+func ParseDuration(s string) (time.Duration, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(s, "+"), "P")
+	if rest == s || rest == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	var d time.Duration
+	inTime := false
+	num := ""
+	units := 0
+	for _, r := range rest {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		num = ""
+		units++
+		switch {
+		case r == 'W':
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D':
+			d += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+	if units == 0 || num != "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
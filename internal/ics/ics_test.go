@@ -0,0 +1,125 @@
+package ics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Prop
+	}{
+		{"SUMMARY:Standup", Prop{"SUMMARY", map[string]string{}, "Standup"}},
+		{"dtstart;value=DATE:20250113", Prop{"DTSTART", map[string]string{"VALUE": "DATE"}, "20250113"}},
+		{"DTSTART;TZID=Asia/Taipei:20250113T090000", Prop{"DTSTART", map[string]string{"TZID": "Asia/Taipei"}, "20250113T090000"}},
+		{`ATTENDEE;CN="Lin, A: PM";ROLE=CHAIR:mailto:a@example.com`,
+			Prop{"ATTENDEE", map[string]string{"CN": "Lin, A: PM", "ROLE": "CHAIR"}, "mailto:a@example.com"}},
+		{`DTSTART;TZID="GMT+8;Taipei":20250113T090000`,
+			Prop{"DTSTART", map[string]string{"TZID": "GMT+8;Taipei"}, "20250113T090000"}},
+		{"END", Prop{"END", map[string]string{}, ""}},
+	}
+	for _, tt := range tests {
+		if got := ParseLine(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"X-WR-CALNAME:Holidays\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:New Year\\, observed\r\n" +
+		"DESCRIPTION:folded\r\n" +
+		"  across lines\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"SUMMARY:Reminder\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Second\r\n" +
+		"CATEGORIES:a\r\n" +
+		"CATEGORIES:b\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events := Events([]byte(data))
+	if len(events) != 2 {
+		t.Fatalf("Events() returned %d events, want 2", len(events))
+	}
+	if got := events[0].Summary(); got != "New Year, observed" {
+		t.Errorf("Summary() = %q, want the event title", got)
+	}
+	if got := events[0].Value("DESCRIPTION"); got != "folded across lines" {
+		t.Errorf("Value(DESCRIPTION) = %q, want the unfolded line", got)
+	}
+	if got := len(events[0].All("SUMMARY")); got != 1 {
+		t.Errorf("event has %d SUMMARY properties, want the alarm's left out", got)
+	}
+	if got := events[1].All("CATEGORIES"); len(got) != 2 || got[0].Value != "a" || got[1].Value != "b" {
+		t.Errorf("All(CATEGORIES) = %+v, want a and b", got)
+	}
+	if _, ok := events[1].Get("DTSTART"); ok {
+		t.Error("Get(DTSTART) found a property the event does not have")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	tests := []struct {
+		prop   Prop
+		want   time.Time
+		isDate bool
+	}{
+		{Prop{Value: "20250113"}, time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local), true},
+		{Prop{Params: map[string]string{"VALUE": "DATE"}, Value: "20250113"}, time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local), true},
+		{Prop{Value: "20250113T010000Z"}, time.Date(2025, 1, 13, 1, 0, 0, 0, time.UTC), false},
+		{Prop{Params: map[string]string{"TZID": "Asia/Taipei"}, Value: "20250113T090000"}, time.Date(2025, 1, 13, 9, 0, 0, 0, taipei), false},
+		{Prop{Params: map[string]string{"TZID": "Taipei Standard Time"}, Value: "20250113T090000"}, time.Date(2025, 1, 13, 9, 0, 0, 0, time.Local), false},
+	}
+	for _, tt := range tests {
+		got, isDate, err := ParseTime(tt.prop)
+		if err != nil || !got.Equal(tt.want) || isDate != tt.isDate {
+			t.Errorf("ParseTime(%+v) = %v, %v, %v, want %v, %v", tt.prop, got, isDate, err, tt.want, tt.isDate)
+		}
+	}
+
+	for _, v := range []string{"2025011", "20251313", "20250113T0900", "20250113T090000+08"} {
+		if _, _, err := ParseTime(Prop{Value: v}); err == nil {
+			t.Errorf("ParseTime(%q) succeeded", v)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"PT1H30M", 90 * time.Minute},
+		{"PT45M", 45 * time.Minute},
+		{"PT15S", 15 * time.Second},
+		{"P1D", 24 * time.Hour},
+		{"P1W", 7 * 24 * time.Hour},
+		{"P1DT2H", 26 * time.Hour},
+		{"+PT1H", time.Hour},
+		{"P0DT0H10M0S", 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "P", "PT", "1H", "PT1H30", "P1H", "-PT1H", "PTXM"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) succeeded", in)
+		}
+	}
+}
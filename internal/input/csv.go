@@ -0,0 +1,109 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/client"
+)
+
+// csvColumns are the columns of CSV/TSV input, in their default order.
+var csvColumns = []string{"date", "project", "activity", "hours", "note"}
+
+// decodeDelimited parses CSV or TSV rows of date,project,activity,hours,note
+// and groups them into weeks beginning on first. A header row naming the
+// columns is optional; without one the default column order is assumed.
+// Rows for the same project and activity within a week are merged.
+func decodeDelimited(data []byte, delim rune, first time.Weekday) ([]Week, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = delim
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range csvColumns {
+		columns[name] = i
+	}
+	hasHeader := len(records) > 0 && isHeader(records[0])
+	if hasHeader {
+		columns = make(map[string]int)
+		for i, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		for _, required := range []string{"date", "project", "hours"} {
+			if _, ok := columns[required]; !ok {
+				return nil, fmt.Errorf("CSV header is missing the %q column", required)
+			}
+		}
+		records = records[1:]
+	}
+
+	g := newWeekGrouper(first)
+	for i, rec := range records {
+		line := i + 1
+		if hasHeader {
+			line++
+		}
+
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[idx])
+		}
+
+		if isBlank(rec) {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", field("date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q (expected YYYY-MM-DD)", line, field("date"))
+		}
+		projectID := field("project")
+		if projectID == "" {
+			return nil, fmt.Errorf("line %d: missing project", line)
+		}
+		hours, err := client.ParseHours(field("hours"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		g.addDay(date, client.SaveEntry{
+			ProjectID:  projectID,
+			ActivityID: field("activity"),
+		}, client.SaveDayEntry{Hours: hours, Note: field("note")})
+	}
+
+	return g.weeks(), nil
+}
+
+// isHeader reports whether a record is a header row rather than data.
+func isHeader(rec []string) bool {
+	for _, f := range rec {
+		if strings.EqualFold(strings.TrimSpace(f), "date") {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlank reports whether every field of a record is empty.
+func isBlank(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,287 @@
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"gopkg.in/yaml.v3"
+)
+
+// NamedEntry is an entry with the project and activity names shown next
+// to it in a week document.
+type NamedEntry struct {
+	client.SaveEntry
+	ProjectName  string
+	ActivityName string
+}
+
+// FormatWeekDocument writes a week as a YAML document for editing by
+// hand: one block per project/activity, commented with its names, whose
+// days are keyed by the weekday of their column date (mon, tue, ...). A
+// day is its hours, or a mapping with hours and note. The projects and
+// activities of the catalog, if given, are listed in a closing comment.
+func FormatWeekDocument(days [7]time.Time, status string, entries []NamedEntry, pa *client.ProjectsAndActivities) ([]byte, error) {
+	if _, err := weekdayColumns(days); err != nil {
+		return nil, err
+	}
+
+	var head strings.Builder
+	fmt.Fprintf(&head, "Week of %s", days[0].Format(dates.Layout))
+	if status != "" {
+		fmt.Fprintf(&head, " (%s)", status)
+	}
+	head.WriteString("\n\nDays: ")
+	for i, d := range days {
+		if i > 0 {
+			head.WriteString(", ")
+		}
+		fmt.Fprintf(&head, "%s %s", weekdayKey(d.Weekday()), d.Format("01/02"))
+	}
+	head.WriteString("\nWrite a day as hours (mon: 8) or with a note (tue: {hours: 2, note: review}).\n")
+	head.WriteString("Delete a block to remove its row. Save and quit to continue;\n")
+	head.WriteString("an empty file cancels.")
+
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, entry := range entries {
+		item := &yaml.Node{Kind: yaml.MappingNode, HeadComment: entryComment(entry)}
+		item.Content = append(item.Content, scalar("project_id"), stringScalar(entry.ProjectID))
+		if entry.ActivityID != "" {
+			item.Content = append(item.Content, scalar("activity_id"), stringScalar(entry.ActivityID))
+		}
+		for i, day := range entry.Days {
+			if i >= 7 || (day.Hours.IsEmpty() && strings.TrimSpace(day.Note) == "") {
+				continue
+			}
+			key := scalar(weekdayKey(days[i].Weekday()))
+			hours := scalar(day.Hours.String())
+			if day.Hours.IsEmpty() {
+				hours = stringScalar("")
+			}
+			if strings.TrimSpace(day.Note) == "" {
+				item.Content = append(item.Content, key, hours)
+				continue
+			}
+			item.Content = append(item.Content, key, &yaml.Node{
+				Kind:    yaml.MappingNode,
+				Style:   yaml.FlowStyle,
+				Content: []*yaml.Node{scalar("hours"), hours, scalar("note"), stringScalar(day.Note)},
+			})
+		}
+		list.Content = append(list.Content, item)
+	}
+
+	doc := &yaml.Node{
+		Kind:        yaml.MappingNode,
+		HeadComment: head.String(),
+		FootComment: catalogComment(pa),
+		Content:     []*yaml.Node{scalar("entries"), list},
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseWeekDocument reads a week document back into sheet rows dated by
+// the week's column dates. Projects and activities may be given by ID
+// or by name, as in imported sheets; with a catalog they are resolved
+// so that two blocks for the same project and activity are an error
+// even when one uses names and the other IDs.
+func ParseWeekDocument(data []byte, days [7]time.Time, pa *client.ProjectsAndActivities) ([]SheetRow, error) {
+	columns, err := weekdayColumns(days)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Entries []map[string]yaml.Node `yaml:"entries"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	var rows []SheetRow
+	seen := make(map[string]int, len(doc.Entries))
+	names := make(map[int]string, len(doc.Entries))
+	for i, entry := range doc.Entries {
+		where := fmt.Sprintf("entry %d", i+1)
+		project := strings.TrimSpace(entry["project_id"].Value)
+		if project == "" {
+			return nil, fmt.Errorf("%s: missing project_id", where)
+		}
+		activity := strings.TrimSpace(entry["activity_id"].Value)
+
+		name := project
+		if activity != "" {
+			name += " / " + activity
+		}
+		target, err := documentTarget(pa, project, activity)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", where, err)
+		}
+		if first, ok := seen[target]; ok {
+			return nil, fmt.Errorf("entry %d (%s) and %s (%s) are the same project and activity; merge them into one block", first, names[first], where, name)
+		}
+		seen[target] = i + 1
+		names[i+1] = name
+
+		keys := make([]string, 0, len(entry))
+		for key := range entry {
+			if key != "project_id" && key != "activity_id" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		keyColumns := make(map[string]int, len(keys))
+		dayKeys := make(map[int]string, len(keys))
+		for _, key := range keys {
+			wd, ok := dates.ParseWeekday(key)
+			if !ok {
+				return nil, fmt.Errorf("%s: unknown key %q (use project_id, activity_id or a weekday such as mon)", where, key)
+			}
+			if other, ok := dayKeys[columns[wd]]; ok {
+				return nil, fmt.Errorf("%s: %s and %s are the same day", where, other, key)
+			}
+			keyColumns[key] = columns[wd]
+			dayKeys[columns[wd]] = key
+		}
+		sort.Slice(keys, func(a, b int) bool { return keyColumns[keys[a]] < keyColumns[keys[b]] })
+
+		for _, key := range keys {
+			node := entry[key]
+			day, err := parseDocumentDay(&node)
+			if err != nil {
+				return nil, fmt.Errorf("%s, %s: %w", where, key, err)
+			}
+			if day.Hours.IsEmpty() && day.Note == "" {
+				continue
+			}
+			rows = append(rows, SheetRow{
+				Source:   fmt.Sprintf("%s, %s", where, key),
+				Date:     days[keyColumns[key]],
+				Project:  project,
+				Activity: activity,
+				Hours:    day.Hours,
+				Note:     day.Note,
+			})
+		}
+	}
+	return rows, nil
+}
+
+// weekdayColumns maps each weekday to its column in the week, and fails
+// unless the column dates are seven consecutive days.
+func weekdayColumns(days [7]time.Time) (map[time.Weekday]int, error) {
+	columns := make(map[time.Weekday]int, 7)
+	for i, d := range days {
+		if !d.Equal(days[0].AddDate(0, 0, i)) {
+			return nil, fmt.Errorf("week columns %s to %s are not seven consecutive days", days[0].Format(dates.Layout), days[6].Format(dates.Layout))
+		}
+		columns[d.Weekday()] = i
+	}
+	return columns, nil
+}
+
+// documentTarget identifies the project and activity of an entry: by
+// their IDs in the catalog if given, else by the text as written.
+func documentTarget(pa *client.ProjectsAndActivities, project, activity string) (string, error) {
+	if pa == nil {
+		return strings.ToLower(project) + "$" + strings.ToLower(activity), nil
+	}
+	p, err := findProject(pa, project)
+	if err != nil {
+		return "", err
+	}
+	if activity == "" {
+		return p.ID + "$", nil
+	}
+	a, err := findActivity(p, activity)
+	if err != nil {
+		return "", err
+	}
+	return p.ID + "$" + a.ID, nil
+}
+
+// parseDocumentDay reads a day given as hours or as {hours, note}.
+func parseDocumentDay(node *yaml.Node) (client.SaveDayEntry, error) {
+	var day struct {
+		Hours string `yaml:"hours"`
+		Note  string `yaml:"note"`
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		day.Hours = node.Value
+		if node.Tag == "!!null" {
+			day.Hours = ""
+		}
+	case yaml.MappingNode:
+		if err := node.Decode(&day); err != nil {
+			return client.SaveDayEntry{}, err
+		}
+	default:
+		return client.SaveDayEntry{}, fmt.Errorf("expected hours or {hours, note}")
+	}
+	hours, err := client.ParseHours(day.Hours)
+	if err != nil {
+		return client.SaveDayEntry{}, err
+	}
+	return client.SaveDayEntry{Hours: hours, Note: strings.TrimSpace(day.Note)}, nil
+}
+
+// weekdayKey is the document key of a weekday: mon, tue, ...
+func weekdayKey(wd time.Weekday) string {
+	return strings.ToLower(wd.String()[:3])
+}
+
+// entryComment names an entry's project and activity.
+func entryComment(entry NamedEntry) string {
+	name := entry.ProjectName
+	if name == "" {
+		name = "project " + entry.ProjectID
+	}
+	if entry.ActivityName != "" {
+		name += " / " + entry.ActivityName
+	}
+	return name
+}
+
+// catalogComment lists the projects and activities that can be added.
+func catalogComment(pa *client.ProjectsAndActivities) string {
+	if pa == nil || len(pa.Projects) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Projects and activities (project_id / activity_id):")
+	for _, p := range pa.Projects {
+		fmt.Fprintf(&b, "\n  %s  %s", p.ID, strings.TrimSpace(p.Name))
+		for _, a := range p.Activities {
+			name := a.FullName
+			if name == "" {
+				name = a.Name
+			}
+			fmt.Fprintf(&b, "\n      %s  %s", a.ID, strings.TrimSpace(name))
+		}
+	}
+	return b.String()
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+// stringScalar is a scalar that stays a string, quoted if it would read
+// as a number.
+func stringScalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,188 @@
+// Package input decodes timecard entries for saving from JSON, YAML, TOML
+// and CSV/TSV files, reads spreadsheets for import, and writes and reads
+// the week documents edited by hand in tcrs edit --editor.
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/user/tcrs/internal/client"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported input formats.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+	FormatCSV  = "csv"
+	FormatTSV  = "tsv"
+	FormatXLSX = "xlsx"
+)
+
+// Week holds the entries to save for one week.
+type Week struct {
+	StartDate string             `json:"week_start_date"`
+	Entries   []client.SaveEntry `json:"entries"`
+	// Dated is set when days were given by date rather than as a days
+	// array. Such input names only some days of the week, so it is laid
+	// over what the week already holds instead of replacing it.
+	Dated bool `json:"-"`
+}
+
+// SaveInput is the document shape of JSON, YAML and TOML input.
+type SaveInput struct {
+	Entries []datedEntry `json:"entries"`
+}
+
+// FormatFromPath guesses the input format from a file extension. Unknown
+// extensions and stdin ("-") are treated as JSON.
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".csv":
+		return FormatCSV
+	case ".tsv", ".tab":
+		return FormatTSV
+	case ".xlsx":
+		return FormatXLSX
+	default:
+		return FormatJSON
+	}
+}
+
+// Decode parses input in the given format into weeks to save, ordered by
+// date. In JSON, YAML and TOML documents a 7-slot days array belongs to
+// the week starting at weekStart, while entries keyed by absolute dates
+// are split into the weeks containing those dates, with weeks beginning
+// on first. CSV and TSV rows carry their own dates and are grouped the
+// same way.
+func Decode(data []byte, format, weekStart string, first time.Weekday) ([]Week, error) {
+	switch format {
+	case FormatJSON, FormatYAML, FormatTOML:
+		entries, err := decodeDocument(data, format)
+		if err != nil {
+			return nil, err
+		}
+		return groupEntries(entries, weekStart, first)
+	case FormatCSV:
+		return decodeDelimited(data, ',', first)
+	case FormatTSV:
+		return decodeDelimited(data, '\t', first)
+	default:
+		return nil, fmt.Errorf("unknown input format %q (use json, yaml, toml, csv or tsv)", format)
+	}
+}
+
+// decodeDocument decodes a JSON, YAML or TOML document. YAML and TOML are
+// converted to JSON first so that all three share the JSON field names and
+// the hours parsing of client.Hours.
+func decodeDocument(data []byte, format string) ([]datedEntry, error) {
+	jsonData := data
+	switch format {
+	case FormatYAML:
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		converted, err := json.Marshal(normalizeDoc(doc))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		jsonData = converted
+	case FormatTOML:
+		var doc map[string]interface{}
+		if err := toml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML: %w", err)
+		}
+		converted, err := json.Marshal(normalizeDoc(doc))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TOML: %w", err)
+		}
+		jsonData = converted
+	}
+
+	var in SaveInput
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	if err := dec.Decode(&in); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", strings.ToUpper(format), err)
+	}
+	return in.Entries, nil
+}
+
+// groupEntries splits decoded entries into weeks.
+func groupEntries(entries []datedEntry, weekStart string, first time.Weekday) ([]Week, error) {
+	g := newWeekGrouper(first)
+	for _, entry := range entries {
+		if len(entry.Dates) == 0 || len(entry.Days) > 0 {
+			g.addWeek(weekStart, entry.SaveEntry)
+		}
+
+		dates := make([]string, 0, len(entry.Dates))
+		for dateStr := range entry.Dates {
+			dates = append(dates, dateStr)
+		}
+		sort.Strings(dates)
+
+		for _, dateStr := range dates {
+			day := entry.Dates[dateStr]
+			date, err := time.Parse("2006-01-02", dateStr)
+			if err != nil {
+				return nil, fmt.Errorf("project %s: invalid date %q (expected YYYY-MM-DD)", entry.ProjectID, dateStr)
+			}
+			dayEntry := entry.SaveEntry
+			dayEntry.Days = nil
+			g.addDay(date, dayEntry, client.SaveDayEntry(day))
+		}
+	}
+	return g.weeks(), nil
+}
+
+// normalizeDoc prepares a decoded YAML or TOML document for JSON: numeric
+// project_id and activity_id values become strings, since users naturally
+// write "project_id: 12345", and date keys parsed as timestamps by YAML are
+// turned back into YYYY-MM-DD strings.
+func normalizeDoc(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, val := range t {
+			if key == "project_id" || key == "activity_id" {
+				switch n := val.(type) {
+				case int, int64, float64, uint64:
+					t[key] = fmt.Sprint(n)
+					continue
+				}
+			}
+			t[key] = normalizeDoc(val)
+		}
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			if ts, ok := key.(time.Time); ok {
+				m[ts.Format("2006-01-02")] = val
+			} else {
+				m[fmt.Sprint(key)] = val
+			}
+		}
+		return normalizeDoc(m)
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeDoc(t[i])
+		}
+	case []map[string]interface{}:
+		for i := range t {
+			t[i] = normalizeDoc(t[i]).(map[string]interface{})
+		}
+	}
+	return v
+}
@@ -0,0 +1,295 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/xlsx"
+)
+
+// ColumnMapping maps the fields of an imported row (date, project,
+// activity, hours, note) to spreadsheet columns, given by header name or
+// 1-based column number. Fields not in the mapping use their own name as
+// the header.
+type ColumnMapping map[string]string
+
+// ParseColumnMapping parses "field=column" pairs such as "date=Day".
+func ParseColumnMapping(pairs []string) (ColumnMapping, error) {
+	m := make(ColumnMapping)
+	for _, pair := range pairs {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || !containsField(field) || strings.TrimSpace(column) == "" {
+			return nil, fmt.Errorf("invalid column mapping %q (use field=column with field one of %s)", pair, strings.Join(csvColumns, ", "))
+		}
+		m[field] = strings.TrimSpace(column)
+	}
+	return m, nil
+}
+
+func containsField(field string) bool {
+	for _, f := range csvColumns {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// SheetRow is one row of an imported spreadsheet, with project and
+// activity as written in the sheet (ID or name).
+type SheetRow struct {
+	Line     int
+	Source   string // where the row came from, if not a sheet line
+	Date     time.Time
+	Project  string
+	Activity string
+	Hours    client.Hours
+	Note     string
+}
+
+// where names the row in error messages.
+func (r SheetRow) where() string {
+	if r.Source != "" {
+		return r.Source
+	}
+	return fmt.Sprintf("line %d", r.Line)
+}
+
+// ReadSheet reads the rows of a CSV, TSV or XLSX file. The first row must
+// be a header unless the mapping gives every field by column number.
+func ReadSheet(data []byte, format string, mapping ColumnMapping) ([]SheetRow, error) {
+	var records [][]string
+	switch format {
+	case FormatCSV, FormatTSV:
+		r := csv.NewReader(bytes.NewReader(data))
+		if format == FormatTSV {
+			r.Comma = '\t'
+		}
+		r.Comment = '#'
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+		var err error
+		if records, err = r.ReadAll(); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", strings.ToUpper(format), err)
+		}
+	case FormatXLSX:
+		var err error
+		if records, err = xlsx.ReadFirstSheet(data); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown import format %q (use csv, tsv or xlsx)", format)
+	}
+
+	// The header is the first row with any content; line numbers count
+	// the blank rows before it
+	first := 0
+	for first < len(records) && isBlank(records[first]) {
+		first++
+	}
+	columns, hasHeader, err := resolveColumns(records[first:], mapping)
+	if err != nil {
+		return nil, err
+	}
+	if hasHeader {
+		first++
+	}
+
+	rows := make([]SheetRow, 0, len(records))
+	for i := first; i < len(records); i++ {
+		rec := records[i]
+		line := i + 1
+		if isBlank(rec) {
+			continue
+		}
+
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[idx])
+		}
+
+		date, err := parseSheetDate(field("date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		project := field("project")
+		if project == "" {
+			return nil, fmt.Errorf("line %d: missing project", line)
+		}
+		hours, err := client.ParseHours(field("hours"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rows = append(rows, SheetRow{
+			Line:     line,
+			Date:     date,
+			Project:  project,
+			Activity: field("activity"),
+			Hours:    hours,
+			Note:     field("note"),
+		})
+	}
+	return rows, nil
+}
+
+// resolveColumns returns the column index of every mapped field and
+// whether the first record is a header.
+func resolveColumns(records [][]string, mapping ColumnMapping) (map[string]int, bool, error) {
+	columns := make(map[string]int)
+	byName := make(map[string]int)
+	if len(records) > 0 {
+		for i, name := range records[0] {
+			byName[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+	}
+
+	hasHeader := false
+	for _, field := range csvColumns {
+		column, ok := mapping[field]
+		if !ok {
+			column = field
+		}
+		if n, err := strconv.Atoi(column); err == nil && n > 0 {
+			columns[field] = n - 1
+			continue
+		}
+		if idx, ok := byName[strings.ToLower(column)]; ok {
+			columns[field] = idx
+			hasHeader = true
+			continue
+		}
+		if field == "date" || field == "project" || field == "hours" {
+			return nil, false, fmt.Errorf("no %q column for %s (map it with --map %s=<column>)", column, field, field)
+		}
+	}
+	return columns, hasHeader, nil
+}
+
+// parseSheetDate parses a date cell: YYYY-MM-DD and the other forms of
+// dates.Parse, or a spreadsheet serial number.
+func parseSheetDate(s string) (time.Time, error) {
+	if serial, err := strconv.ParseFloat(s, 64); err == nil && serial > 1 && serial < 100000 {
+		return xlsx.SerialDate(serial), nil
+	}
+	t, err := dates.Parse(s, time.Now())
+	if err != nil || s == "" {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	return t, nil
+}
+
+// Catalog returns the projects and activities available on a date.
+type Catalog func(date string) (*client.ProjectsAndActivities, error)
+
+// ResolveSheet turns sheet rows into weeks beginning on first, resolving
+// project and activity names to IDs with the catalog of each week. A
+// project matches by ID or name; an activity by ID, name, full name or
+// WBS code. Rows for the same project and activity within a week are
+// merged.
+func ResolveSheet(rows []SheetRow, catalog Catalog, first time.Weekday) ([]Week, error) {
+	catalogs := make(map[string]*client.ProjectsAndActivities)
+	g := newWeekGrouper(first)
+
+	for _, row := range rows {
+		week := dates.WeekStartOn(row.Date, first).Format(dates.Layout)
+		pa, ok := catalogs[week]
+		if !ok {
+			var err error
+			if pa, err = catalog(week); err != nil {
+				return nil, fmt.Errorf("failed to get projects for week starting %s: %w", week, err)
+			}
+			catalogs[week] = pa
+		}
+
+		project, err := findProject(pa, row.Project)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", row.where(), err)
+		}
+		activityID := ""
+		if row.Activity != "" {
+			act, err := findActivity(project, row.Activity)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", row.where(), err)
+			}
+			activityID = act.ID
+		}
+
+		g.addDay(row.Date, client.SaveEntry{
+			ProjectID:  project.ID,
+			ActivityID: activityID,
+		}, client.SaveDayEntry{Hours: row.Hours, Note: row.Note})
+	}
+	return g.weeks(), nil
+}
+
+// findProject looks up a project by ID, then by case-insensitive name.
+func findProject(pa *client.ProjectsAndActivities, ref string) (client.Project, error) {
+	for _, p := range pa.Projects {
+		if p.ID == ref {
+			return p, nil
+		}
+	}
+	var matches []client.Project
+	for _, p := range pa.Projects {
+		if strings.EqualFold(strings.TrimSpace(p.Name), ref) {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return client.Project{}, fmt.Errorf("unknown project %q", ref)
+	default:
+		return client.Project{}, fmt.Errorf("project name %q is ambiguous (%s); use its ID", ref, projectIDs(matches))
+	}
+}
+
+// findActivity looks up an activity of a project by ID, then by name,
+// full name or WBS code.
+func findActivity(p client.Project, ref string) (client.Activity, error) {
+	for _, a := range p.Activities {
+		if a.ID == ref {
+			return a, nil
+		}
+	}
+	var matches []client.Activity
+	for _, a := range p.Activities {
+		if strings.EqualFold(strings.TrimSpace(a.Name), ref) || strings.EqualFold(strings.TrimSpace(a.FullName), ref) || (a.WBS != "" && a.WBS == ref) {
+			matches = append(matches, a)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return client.Activity{}, fmt.Errorf("unknown activity %q in project %s", ref, p.Name)
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, a := range matches {
+			ids = append(ids, a.ID)
+		}
+		sort.Strings(ids)
+		return client.Activity{}, fmt.Errorf("activity name %q is ambiguous in project %s (%s); use its ID", ref, p.Name, strings.Join(ids, ", "))
+	}
+}
+
+func projectIDs(projects []client.Project) string {
+	ids := make([]string, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ", ")
+}
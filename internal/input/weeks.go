@@ -0,0 +1,81 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+)
+
+// datedEntry is an input entry. Besides the 7-slot days array of
+// client.SaveEntry it may key days by absolute date, which lets a single
+// entry span several weeks.
+type datedEntry struct {
+	client.SaveEntry
+	Dates map[string]datedDay `json:"dates,omitempty"`
+}
+
+// datedDay is a day in the dates map. A bare number or string is
+// shorthand for {"hours": ...}.
+type datedDay client.SaveDayEntry
+
+// UnmarshalJSON accepts a day object or a bare hours value.
+func (d *datedDay) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return json.Unmarshal(data, (*client.SaveDayEntry)(d))
+	}
+	*d = datedDay{}
+	return json.Unmarshal(data, &d.Hours)
+}
+
+// weekGrouper collects entries and groups them into weeks beginning on
+// a given weekday.
+type weekGrouper struct {
+	first  time.Weekday
+	byWeek map[string][]client.SaveEntry
+	dated  map[string]bool
+}
+
+func newWeekGrouper(first time.Weekday) *weekGrouper {
+	return &weekGrouper{first: first, byWeek: make(map[string][]client.SaveEntry), dated: make(map[string]bool)}
+}
+
+// addWeek adds entries whose days array belongs to the week at start.
+func (g *weekGrouper) addWeek(start string, entries ...client.SaveEntry) {
+	g.byWeek[start] = append(g.byWeek[start], entries...)
+}
+
+// addDay adds a single day of a project/activity, placing it in the
+// week containing date.
+func (g *weekGrouper) addDay(date time.Time, entry client.SaveEntry, day client.SaveDayEntry) {
+	start := dates.WeekStartOn(date, g.first)
+	dayIdx := int(date.Sub(start).Hours() / 24)
+
+	entry.Days = make([]client.SaveDayEntry, 7)
+	entry.Days[dayIdx] = day
+	g.addWeek(start.Format(dates.Layout), entry)
+	g.dated[start.Format(dates.Layout)] = true
+}
+
+// weeks returns the collected weeks in date order, with duplicate
+// project/activity rows merged.
+func (g *weekGrouper) weeks() []Week {
+	starts := make([]string, 0, len(g.byWeek))
+	for start := range g.byWeek {
+		starts = append(starts, start)
+	}
+	sort.Strings(starts)
+
+	weeks := make([]Week, 0, len(starts))
+	for _, start := range starts {
+		weeks = append(weeks, Week{
+			StartDate: start,
+			Entries:   client.CompactEntries(g.byWeek[start]),
+			Dated:     g.dated[start],
+		})
+	}
+	return weeks
+}
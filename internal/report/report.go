@@ -0,0 +1,131 @@
+// Package report aggregates the hours of week timecards over a date range.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+)
+
+// Ways rows of a report can be grouped.
+const (
+	GroupByProject  = "project"
+	GroupByActivity = "activity"
+	GroupByWeek     = "week"
+	GroupByDay      = "day"
+)
+
+// Row is one group of a report.
+type Row struct {
+	Key     string  `json:"key"`
+	Label   string  `json:"label"`
+	Hours   float64 `json:"hours"`
+	Percent float64 `json:"percent"` // share of the report total
+}
+
+// Report is the hours of a date range grouped one way.
+type Report struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	GroupBy string  `json:"group_by"`
+	Rows    []Row   `json:"rows"`
+	Total   float64 `json:"total"`
+}
+
+// ValidateGroupBy checks that groupBy is one of the GroupBy values.
+func ValidateGroupBy(groupBy string) error {
+	switch groupBy {
+	case GroupByProject, GroupByActivity, GroupByWeek, GroupByDay:
+		return nil
+	}
+	return fmt.Errorf("unknown group %q (use project, activity, week or day)", groupBy)
+}
+
+// Build aggregates the hours of weeks falling between from and to
+// (inclusive). Days outside the range are left out, so weeks straddling
+// its ends only count partially. Project and activity rows are ordered
+// by hours, most first; week and day rows by date.
+func Build(weeks []*client.WeekTimecard, from, to time.Time, groupBy string) (*Report, error) {
+	if err := ValidateGroupBy(groupBy); err != nil {
+		return nil, err
+	}
+
+	r := &Report{
+		From:    from.Format(dates.Layout),
+		To:      to.Format(dates.Layout),
+		GroupBy: groupBy,
+		Rows:    make([]Row, 0),
+	}
+	byKey := make(map[string]int)
+	add := func(key, label string, hours float64) {
+		idx, ok := byKey[key]
+		if !ok {
+			idx = len(r.Rows)
+			byKey[key] = idx
+			r.Rows = append(r.Rows, Row{Key: key, Label: label})
+		}
+		r.Rows[idx].Hours += hours
+		r.Total += hours
+	}
+
+	for _, week := range weeks {
+		for _, entry := range week.Entries {
+			for dayIdx, day := range entry.Days {
+				hours := day.Hours.Float()
+				if hours == 0 {
+					continue
+				}
+				date, ok := week.ColumnDate(dayIdx)
+				if !ok || date.Before(from) || date.After(to) {
+					continue
+				}
+				key, label := rowKey(groupBy, week, entry, date)
+				add(key, label, hours)
+			}
+		}
+	}
+
+	for i := range r.Rows {
+		if r.Total > 0 {
+			r.Rows[i].Percent = r.Rows[i].Hours / r.Total * 100
+		}
+	}
+	switch groupBy {
+	case GroupByProject, GroupByActivity:
+		sort.SliceStable(r.Rows, func(i, j int) bool {
+			return r.Rows[i].Hours > r.Rows[j].Hours
+		})
+	default:
+		sort.SliceStable(r.Rows, func(i, j int) bool {
+			return r.Rows[i].Key < r.Rows[j].Key
+		})
+	}
+	return r, nil
+}
+
+// rowKey returns the key and label of the row an entry's day belongs to.
+func rowKey(groupBy string, week *client.WeekTimecard, entry client.WeekEntry, date time.Time) (string, string) {
+	project := entry.ProjectName
+	if project == "" {
+		project = entry.ProjectID
+	}
+
+	switch groupBy {
+	case GroupByActivity:
+		activityID := entry.ActivityID()
+		activity := entry.ActivityName
+		if activity == "" {
+			activity = activityID
+		}
+		return entry.ProjectID + "/" + activityID, project + " / " + activity
+	case GroupByWeek:
+		return week.WeekStartDate, week.WeekStartDate
+	case GroupByDay:
+		return date.Format(dates.Layout), date.Format("2006-01-02 (Mon)")
+	default:
+		return entry.ProjectID, project
+	}
+}
@@ -0,0 +1,86 @@
+// Package suggest proposes timecard entries from what a user already
+// left behind elsewhere: their git commits and calendar meetings.
+package suggest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Commit is a commit made by the user.
+type Commit struct {
+	Repo    string // repository path as given
+	Date    time.Time
+	Subject string
+}
+
+// RepoName returns the name a repository is matched by in the mapping:
+// the base name of its directory.
+func RepoName(repo string) string {
+	abs, err := filepath.Abs(repo)
+	if err != nil {
+		abs = repo
+	}
+	return filepath.Base(abs)
+}
+
+// GitAuthor returns the user.email configured for a repository.
+func GitAuthor(ctx context.Context, repo string) (string, error) {
+	out, err := git(ctx, repo, "config", "user.email")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// GitCommits lists the non-merge commits of all branches in repo authored
+// by author between from and to (inclusive days), in local time.
+func GitCommits(ctx context.Context, repo, author string, from, to time.Time) ([]Commit, error) {
+	since := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	until := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, time.Local)
+	out, err := git(ctx, repo, "log", "--all", "--no-merges",
+		"--author="+author,
+		"--since="+since.Format(time.RFC3339),
+		"--until="+until.Format(time.RFC3339),
+		"--format=%aI%x09%s")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(out, "\n") {
+		stamp, subject, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, stamp)
+		if err != nil {
+			continue
+		}
+		t = t.In(time.Local)
+		if t.Before(since) || !t.Before(until) {
+			continue // --since/--until filter on committer date
+		}
+		commits = append(commits, Commit{Repo: repo, Date: t, Subject: strings.TrimSpace(subject)})
+	}
+	return commits, nil
+}
+
+func git(ctx context.Context, repo string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repo}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s in %s: %s", args[0], repo, msg)
+		}
+		return "", fmt.Errorf("git %s in %s: %w", args[0], repo, err)
+	}
+	return stdout.String(), nil
+}
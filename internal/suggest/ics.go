@@ -0,0 +1,193 @@
+package suggest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/ics"
+	"github.com/user/tcrs/internal/input"
+	"github.com/user/tcrs/internal/tracker"
+)
+
+// Meeting is one occurrence of a timed calendar event.
+type Meeting struct {
+	Start, End time.Time
+	Summary    string
+	Categories []string
+}
+
+// ReadICS returns the meetings of an iCalendar file (as exported by
+// Outlook or Google Calendar) that fall between from and to (inclusive
+// days, local time), cut to that range and in start order. Recurring
+// events are expanded, with their exceptions and moved occurrences.
+// All-day events, cancelled events and events marked free are left out.
+func ReadICS(data []byte, from, to time.Time) ([]Meeting, error) {
+	rangeStart := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	rangeEnd := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, time.Local)
+
+	events := ics.Events(data)
+
+	// Occurrences moved or cancelled by an override are not expanded
+	moved := make(map[string]bool)
+	for _, ev := range events {
+		if p, ok := ev.Get("RECURRENCE-ID"); ok {
+			t, _, err := ics.ParseTime(p)
+			if err != nil {
+				return nil, fmt.Errorf("event %q: RECURRENCE-ID: %w", ev.Summary(), err)
+			}
+			moved[ev.Value("UID")+"@"+strconv.FormatInt(t.Unix(), 10)] = true
+		}
+	}
+
+	var meetings []Meeting
+	for _, ev := range events {
+		if strings.EqualFold(ev.Value("STATUS"), "CANCELLED") || strings.EqualFold(ev.Value("TRANSP"), "TRANSPARENT") {
+			continue
+		}
+		start, end, allDay, err := eventTimes(ev)
+		if err != nil {
+			return nil, err
+		}
+		if allDay {
+			continue
+		}
+
+		starts := []time.Time{start}
+		if rule, ok := ev.Get("RRULE"); ok {
+			if _, override := ev.Get("RECURRENCE-ID"); !override {
+				if starts, err = expandRRULE(rule.Value, start, rangeEnd); err != nil {
+					return nil, fmt.Errorf("event %q: %w", ev.Summary(), err)
+				}
+				if starts, err = excluding(ev, starts, moved); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		var categories []string
+		for _, p := range ev.All("CATEGORIES") {
+			for _, c := range strings.Split(p.Value, ",") {
+				if c = strings.TrimSpace(ics.Unescape(c)); c != "" {
+					categories = append(categories, c)
+				}
+			}
+		}
+		for _, s := range starts {
+			e := s.Add(end.Sub(start))
+			if !e.After(rangeStart) || !s.Before(rangeEnd) {
+				continue
+			}
+			if s.Before(rangeStart) {
+				s = rangeStart
+			}
+			if e.After(rangeEnd) {
+				e = rangeEnd
+			}
+			meetings = append(meetings, Meeting{Start: s, End: e, Summary: ev.Summary(), Categories: categories})
+		}
+	}
+
+	sort.SliceStable(meetings, func(i, j int) bool {
+		return meetings[i].Start.Before(meetings[j].Start)
+	})
+	return meetings, nil
+}
+
+// FromMeetings proposes import rows from meetings. Meetings are mapped to
+// TCRS projects and activities by the ics rules of the mapping: contains
+// matches the meeting title, match its categories. Time spent in two meetings at once
+// counts once. Hours are summed per day and target and rounded, and
+// meeting titles become the note. Meetings no rule matches are returned
+// rather than being an error, as calendars hold more than work.
+func FromMeetings(meetings []Meeting, mapping *tracker.Mapping, rounding tracker.Rounding) ([]input.SheetRow, []Meeting, error) {
+	var spans []tracker.Span
+	var unmatched []Meeting
+	busyUntil := time.Time{}
+	for _, m := range meetings {
+		span := tracker.Span{Source: tracker.SourceICS, Tags: m.Categories, Description: m.Summary}
+		if _, _, ok := mapping.Match(span); !ok {
+			unmatched = append(unmatched, m)
+			continue
+		}
+
+		start, end := m.Start.Local(), m.End.Local()
+		if start.Before(busyUntil) {
+			start = busyUntil
+		}
+		if end.After(busyUntil) {
+			busyUntil = end
+		}
+		// Split at midnight so each day gets its own part
+		for start.Before(end) {
+			midnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, time.Local)
+			partEnd := end
+			if partEnd.After(midnight) {
+				partEnd = midnight
+			}
+			span.Date = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+			span.Hours = partEnd.Sub(start).Hours()
+			spans = append(spans, span)
+			start = partEnd
+		}
+	}
+
+	rows, err := tracker.Rows(spans, mapping, rounding)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, unmatched, nil
+}
+
+// eventTimes returns the start and end of the event's first occurrence and
+// whether it is an all-day event.
+func eventTimes(ev ics.Event) (start, end time.Time, allDay bool, err error) {
+	p, ok := ev.Get("DTSTART")
+	if !ok {
+		return start, end, false, fmt.Errorf("event %q has no DTSTART", ev.Summary())
+	}
+	if start, allDay, err = ics.ParseTime(p); err != nil {
+		return start, end, false, fmt.Errorf("event %q: DTSTART: %w", ev.Summary(), err)
+	}
+
+	end = start
+	if p, ok := ev.Get("DTEND"); ok {
+		if end, _, err = ics.ParseTime(p); err != nil {
+			return start, end, false, fmt.Errorf("event %q: DTEND: %w", ev.Summary(), err)
+		}
+	} else if p, ok := ev.Get("DURATION"); ok {
+		d, err := ics.ParseDuration(p.Value)
+		if err != nil {
+			return start, end, false, fmt.Errorf("event %q: DURATION: %w", ev.Summary(), err)
+		}
+		end = start.Add(d)
+	}
+	if end.Before(start) {
+		end = start
+	}
+	return start, end, allDay, nil
+}
+
+// excluding drops the starts of ev listed in EXDATE or moved by an override.
+func excluding(ev ics.Event, starts []time.Time, moved map[string]bool) ([]time.Time, error) {
+	excluded := make(map[int64]bool)
+	for _, p := range ev.All("EXDATE") {
+		for _, v := range strings.Split(p.Value, ",") {
+			t, _, err := ics.ParseTime(ics.Prop{Params: p.Params, Value: strings.TrimSpace(v)})
+			if err != nil {
+				return nil, fmt.Errorf("event %q: EXDATE: %w", ev.Summary(), err)
+			}
+			excluded[t.Unix()] = true
+		}
+	}
+	uid := ev.Value("UID")
+	kept := starts[:0]
+	for _, s := range starts {
+		if !excluded[s.Unix()] && !moved[uid+"@"+strconv.FormatInt(s.Unix(), 10)] {
+			kept = append(kept, s)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,205 @@
+package suggest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// googleExport is trimmed from a Google Calendar export: a zone
+// definition, a daily standup with a skipped and a moved occurrence,
+// an alarm, an all-day event, a UTC event with a folded and escaped
+// title, and events that are free or cancelled.
+const googleExport = `BEGIN:VCALENDAR
+PRODID:-//Google Inc//Google Calendar 70.9054//EN
+VERSION:2.0
+CALSCALE:GREGORIAN
+METHOD:PUBLISH
+X-WR-CALNAME:work
+X-WR-TIMEZONE:Asia/Taipei
+BEGIN:VTIMEZONE
+TZID:Asia/Taipei
+X-LIC-LOCATION:Asia/Taipei
+BEGIN:STANDARD
+TZOFFSETFROM:+0800
+TZOFFSETTO:+0800
+TZNAME:CST
+DTSTART:19700101T000000
+END:STANDARD
+END:VTIMEZONE
+BEGIN:VEVENT
+DTSTART;TZID=Asia/Taipei:20250106T093000
+DTEND;TZID=Asia/Taipei:20250106T094500
+RRULE:FREQ=WEEKLY;WKST=SU;BYDAY=MO,TU,WE,TH,FR
+EXDATE;TZID=Asia/Taipei:20250114T093000
+DTSTAMP:20250120T020000Z
+UID:3kq8v1c2g5h7j9l0n2p4r6t8v0@google.com
+CREATED:20241230T030000Z
+LAST-MODIFIED:20250110T030000Z
+SEQUENCE:1
+STATUS:CONFIRMED
+SUMMARY:Standup
+TRANSP:OPAQUE
+BEGIN:VALARM
+ACTION:DISPLAY
+DESCRIPTION:This is an event reminder
+TRIGGER:-P0DT0H10M0S
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+DTSTART;TZID=Asia/Taipei:20250115T100000
+DTEND;TZID=Asia/Taipei:20250115T101500
+DTSTAMP:20250120T020000Z
+UID:3kq8v1c2g5h7j9l0n2p4r6t8v0@google.com
+RECURRENCE-ID;TZID=Asia/Taipei:20250115T093000
+SEQUENCE:2
+STATUS:CONFIRMED
+SUMMARY:Standup
+TRANSP:OPAQUE
+END:VEVENT
+BEGIN:VEVENT
+DTSTART;VALUE=DATE:20250117
+DTEND;VALUE=DATE:20250118
+UID:0a1b2c3d4e5f@google.com
+SUMMARY:Team offsite
+TRANSP:TRANSPARENT
+END:VEVENT
+BEGIN:VEVENT
+DTSTART:20250116T060000Z
+DTEND:20250116T070000Z
+UID:9z8y7x6w5v@google.com
+DESCRIPTION:Agenda:\n- flows\n- rollout
+SUMMARY:Design review\, payments and refunds for the new checkout pag
+ e
+STATUS:CONFIRMED
+TRANSP:OPAQUE
+END:VEVENT
+BEGIN:VEVENT
+DTSTART:20250117T020000Z
+DTEND:20250117T030000Z
+UID:free@google.com
+SUMMARY:Focus time
+TRANSP:TRANSPARENT
+END:VEVENT
+BEGIN:VEVENT
+DTSTART:20250117T050000Z
+DTEND:20250117T060000Z
+UID:cancelled@google.com
+SUMMARY:Vendor call
+STATUS:CANCELLED
+END:VEVENT
+END:VCALENDAR
+`
+
+// outlookExport is trimmed from an Outlook export, which names zones
+// by their Windows names and writes WKST=SU on weekly series.
+const outlookExport = `BEGIN:VCALENDAR
+PRODID:-//Microsoft Corporation//Outlook 16.0 MIMEDIR//EN
+VERSION:2.0
+METHOD:PUBLISH
+X-MS-OLK-FORCEINSPECTOROPEN:TRUE
+BEGIN:VTIMEZONE
+TZID:Taipei Standard Time
+BEGIN:STANDARD
+DTSTART:16010101T000000
+TZOFFSETFROM:+0800
+TZOFFSETTO:+0800
+END:STANDARD
+END:VTIMEZONE
+BEGIN:VEVENT
+CATEGORIES:Meetings,Project X
+CLASS:PUBLIC
+CREATED:20241201T010000Z
+DTEND;TZID="Taipei Standard Time":20241210T150000
+DTSTAMP:20250101T000000Z
+DTSTART;TZID="Taipei Standard Time":20241210T140000
+LAST-MODIFIED:20241201T010000Z
+PRIORITY:5
+RRULE:FREQ=MONTHLY;COUNT=6;BYDAY=2TU
+SEQUENCE:0
+SUMMARY;LANGUAGE=en-us:Team sync
+TRANSP:OPAQUE
+UID:040000008200E00074C5B7101A82E00800000000A0B1C2D3E4F5@outlook
+X-MICROSOFT-CDO-BUSYSTATUS:BUSY
+X-MICROSOFT-CDO-IMPORTANCE:1
+BEGIN:VALARM
+TRIGGER:-PT15M
+ACTION:DISPLAY
+DESCRIPTION:Reminder
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+CLASS:PUBLIC
+DTEND;TZID="Taipei Standard Time":20250106T163000
+DTSTART;TZID="Taipei Standard Time":20250106T160000
+RRULE:FREQ=WEEKLY;UNTIL=20250303T080000Z;INTERVAL=2;BYDAY=MO;WKST=SU
+SUMMARY;LANGUAGE=en-us:1:1
+TRANSP:OPAQUE
+UID:040000008200E00074C5B7101A82E00800000000F5E4D3C2B1A0@outlook
+X-MICROSOFT-CDO-BUSYSTATUS:BUSY
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestReadICS(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	local := time.Local
+	time.Local = taipei
+	t.Cleanup(func() { time.Local = local })
+
+	day := func(s string) time.Time {
+		v, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		from, to string
+		want     []string
+	}{
+		{"google", googleExport, "2025-01-13", "2025-01-19", []string{
+			"2025-01-13 09:30-09:45 Standup []",
+			"2025-01-15 10:00-10:15 Standup []",
+			"2025-01-16 09:30-09:45 Standup []",
+			"2025-01-16 14:00-15:00 Design review, payments and refunds for the new checkout page []",
+			"2025-01-17 09:30-09:45 Standup []",
+		}},
+		{"outlook", outlookExport, "2025-01-01", "2025-03-31", []string{
+			"2025-01-06 16:00-16:30 1:1 []",
+			"2025-01-14 14:00-15:00 Team sync [Meetings, Project X]",
+			"2025-01-20 16:00-16:30 1:1 []",
+			"2025-02-03 16:00-16:30 1:1 []",
+			"2025-02-11 14:00-15:00 Team sync [Meetings, Project X]",
+			"2025-02-17 16:00-16:30 1:1 []",
+			"2025-03-03 16:00-16:30 1:1 []",
+			"2025-03-11 14:00-15:00 Team sync [Meetings, Project X]",
+		}},
+		{"outlook last occurrence", outlookExport, "2025-05-12", "2025-05-18", []string{
+			"2025-05-13 14:00-15:00 Team sync [Meetings, Project X]",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := strings.ReplaceAll(tt.data, "\n", "\r\n")
+			meetings, err := ReadICS([]byte(data), day(tt.from), day(tt.to))
+			if err != nil {
+				t.Fatalf("ReadICS: %v", err)
+			}
+			var got []string
+			for _, m := range meetings {
+				got = append(got, m.Start.Format("2006-01-02 15:04")+"-"+m.End.Format("15:04")+" "+m.Summary+
+					" ["+strings.Join(m.Categories, ", ")+"]")
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ReadICS\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
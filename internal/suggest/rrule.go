@@ -0,0 +1,263 @@
+package suggest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/ics"
+)
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// byDay is a BYDAY entry such as TU, 2TU or -1FR.
+type byDay struct {
+	n       int // occurrence within the month, 0 for every
+	weekday time.Weekday
+}
+
+// expandRRULE returns the starts of a recurring event beginning at start
+// that lie before end. It supports DAILY, WEEKLY, MONTHLY and YEARLY
+// rules with INTERVAL, COUNT, UNTIL, WKST, BYDAY and BYMONTHDAY, which
+// covers what calendar apps write for meeting series. Combinations it
+// cannot expand exactly are an error rather than a guess.
+func expandRRULE(rule string, start, end time.Time) ([]time.Time, error) {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		parts[strings.ToUpper(k)] = strings.ToUpper(v)
+	}
+	for k := range parts {
+		switch k {
+		case "FREQ", "INTERVAL", "COUNT", "UNTIL", "BYDAY", "BYMONTHDAY", "WKST":
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part %s", k)
+		}
+	}
+
+	freq := parts["FREQ"]
+	switch freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported RRULE FREQ %q", freq)
+	}
+	interval := 1
+	if v, ok := parts["INTERVAL"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid RRULE INTERVAL %q", v)
+		}
+		interval = n
+	}
+	count := -1
+	if v, ok := parts["COUNT"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid RRULE COUNT %q", v)
+		}
+		count = n
+	}
+	if v, ok := parts["UNTIL"]; ok {
+		until, _, err := ics.ParseTime(ics.Prop{Value: v})
+		if err != nil {
+			return nil, fmt.Errorf("invalid RRULE UNTIL %q", v)
+		}
+		if len(v) == 8 {
+			until = until.AddDate(0, 0, 1) // the whole last day
+		} else {
+			until = until.Add(time.Second)
+		}
+		if until.Before(end) {
+			end = until
+		}
+	}
+	wkst := time.Monday
+	if v, ok := parts["WKST"]; ok {
+		wd, ok := icsWeekdays[v]
+		if !ok {
+			return nil, fmt.Errorf("invalid RRULE WKST %q", v)
+		}
+		wkst = wd
+	}
+	var days []byDay
+	if v, ok := parts["BYDAY"]; ok {
+		for _, d := range strings.Split(v, ",") {
+			if len(d) < 2 {
+				return nil, fmt.Errorf("invalid RRULE BYDAY %q", v)
+			}
+			wd, ok := icsWeekdays[d[len(d)-2:]]
+			if !ok {
+				return nil, fmt.Errorf("invalid RRULE BYDAY %q", v)
+			}
+			n := 0
+			if prefix := d[:len(d)-2]; prefix != "" {
+				var err error
+				if n, err = strconv.Atoi(strings.TrimPrefix(prefix, "+")); err != nil || n == 0 || n < -5 || n > 5 {
+					return nil, fmt.Errorf("invalid RRULE BYDAY %q", v)
+				}
+				if freq != "MONTHLY" {
+					return nil, fmt.Errorf("unsupported RRULE BYDAY %q with FREQ=%s", d, freq)
+				}
+			}
+			days = append(days, byDay{n, wd})
+		}
+	}
+	var monthDays []int
+	if v, ok := parts["BYMONTHDAY"]; ok {
+		for _, d := range strings.Split(v, ",") {
+			n, err := strconv.Atoi(d)
+			if err != nil || n == 0 || n < -31 || n > 31 {
+				return nil, fmt.Errorf("invalid RRULE BYMONTHDAY %q", v)
+			}
+			monthDays = append(monthDays, n)
+		}
+		if freq != "DAILY" && freq != "MONTHLY" {
+			return nil, fmt.Errorf("unsupported RRULE BYMONTHDAY with FREQ=%s", freq)
+		}
+	}
+	if freq == "YEARLY" && len(days) > 0 {
+		return nil, fmt.Errorf("unsupported RRULE BYDAY with FREQ=YEARLY")
+	}
+
+	// at places start's time of day on a date
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	var starts []time.Time
+	seen := 0
+	for period := 0; ; period += interval {
+		var candidates []time.Time
+		var begin time.Time // beginning of the period
+		switch freq {
+		case "DAILY":
+			d := start.AddDate(0, 0, period)
+			begin = d
+			if (len(days) == 0 || matchesWeekday(days, d.Weekday())) && (len(monthDays) == 0 || matchesMonthDay(monthDays, d)) {
+				candidates = append(candidates, d)
+			}
+		case "WEEKLY":
+			// Weeks begin on WKST, which decides the weeks INTERVAL skips
+			weekStart := start.AddDate(0, 0, -((int(start.Weekday())-int(wkst)+7)%7)+7*period)
+			begin = time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, start.Location())
+			if len(days) == 0 {
+				candidates = append(candidates, start.AddDate(0, 0, 7*period))
+			}
+			for _, bd := range days {
+				d := weekStart.AddDate(0, 0, (int(bd.weekday)-int(wkst)+7)%7)
+				candidates = append(candidates, at(d.Year(), d.Month(), d.Day()))
+			}
+		case "MONTHLY":
+			first := time.Date(start.Year(), start.Month()+time.Month(period), 1, 0, 0, 0, 0, start.Location())
+			begin = first
+			for _, d := range monthCandidates(first, days, monthDays, start.Day()) {
+				candidates = append(candidates, at(first.Year(), first.Month(), d))
+			}
+		case "YEARLY":
+			y := start.Year() + period
+			begin = time.Date(y, time.January, 1, 0, 0, 0, 0, start.Location())
+			if d := at(y, start.Month(), start.Day()); d.Month() == start.Month() {
+				candidates = append(candidates, d)
+			}
+		}
+
+		if !begin.Before(end) {
+			break
+		}
+
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+		for _, c := range candidates {
+			if c.Before(start) {
+				continue
+			}
+			if !c.Before(end) || (count >= 0 && seen >= count) {
+				return starts, nil
+			}
+			starts = append(starts, c)
+			seen++
+		}
+	}
+	return starts, nil
+}
+
+// monthCandidates returns the days of the month beginning at first
+// selected by BYDAY and BYMONTHDAY (both when both are given), or the
+// start's day of the month.
+func monthCandidates(first time.Time, days []byDay, monthDays []int, startDay int) []int {
+	length := first.AddDate(0, 1, -1).Day()
+	byMonthDay := make(map[int]bool)
+	for _, d := range monthDays {
+		if d < 0 {
+			d = length + d + 1
+		}
+		if d >= 1 && d <= length {
+			byMonthDay[d] = true
+		}
+	}
+	byWeekday := make(map[int]bool)
+	for _, bd := range days {
+		firstMatch := 1 + (int(bd.weekday)-int(first.Weekday())+7)%7
+		switch {
+		case bd.n == 0:
+			for d := firstMatch; d <= length; d += 7 {
+				byWeekday[d] = true
+			}
+		case bd.n > 0:
+			byWeekday[firstMatch+7*(bd.n-1)] = true
+		default:
+			last := firstMatch
+			for last+7 <= length {
+				last += 7
+			}
+			byWeekday[last+7*(bd.n+1)] = true
+		}
+	}
+
+	var candidates []int
+	for d := 1; d <= length; d++ {
+		var selected bool
+		switch {
+		case len(days) > 0 && len(monthDays) > 0:
+			selected = byWeekday[d] && byMonthDay[d]
+		case len(monthDays) > 0:
+			selected = byMonthDay[d]
+		case len(days) > 0:
+			selected = byWeekday[d]
+		default:
+			selected = d == startDay
+		}
+		if selected {
+			candidates = append(candidates, d)
+		}
+	}
+	return candidates
+}
+
+func matchesWeekday(days []byDay, wd time.Weekday) bool {
+	for _, d := range days {
+		if d.weekday == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthDay reports whether t falls on one of the days of the
+// month, counting negative days from the end of the month.
+func matchesMonthDay(monthDays []int, t time.Time) bool {
+	length := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, d := range monthDays {
+		if d == t.Day() || d < 0 && length+d+1 == t.Day() {
+			return true
+		}
+	}
+	return false
+}
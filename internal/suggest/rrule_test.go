@@ -0,0 +1,121 @@
+package suggest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandRRULE(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	at := func(s string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, ny)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	far := at("2030-01-01 00:00")
+
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		end   string // default: far in the future
+		want  []string
+	}{
+		{"daily count", "FREQ=DAILY;COUNT=3", "2025-01-13 09:00", "", []string{
+			"2025-01-13 09:00", "2025-01-14 09:00", "2025-01-15 09:00"}},
+		{"daily until date", "FREQ=DAILY;UNTIL=20250115", "2025-01-13 09:00", "", []string{
+			"2025-01-13 09:00", "2025-01-14 09:00", "2025-01-15 09:00"}},
+		{"daily by weekday", "FREQ=DAILY;BYDAY=MO,WE,FR", "2025-01-13 09:00", "2025-01-20 00:00", []string{
+			"2025-01-13 09:00", "2025-01-15 09:00", "2025-01-17 09:00"}},
+		{"daily until end", "FREQ=DAILY", "2025-01-13 09:00", "2025-01-15 09:00", []string{
+			"2025-01-13 09:00", "2025-01-14 09:00"}},
+		{"count zero", "FREQ=DAILY;COUNT=0", "2025-01-13 09:00", "", nil},
+		// first and last day of every month
+		{"daily by month day", "FREQ=DAILY;BYMONTHDAY=1,-1;COUNT=4", "2025-01-01 09:00", "", []string{
+			"2025-01-01 09:00", "2025-01-31 09:00", "2025-02-01 09:00", "2025-02-28 09:00"}},
+		{"weekly until UTC", "FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20250123T235959Z", "2025-01-14 09:00", "", []string{
+			"2025-01-14 09:00", "2025-01-16 09:00", "2025-01-21 09:00", "2025-01-23 09:00"}},
+		{"biweekly", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "2025-01-13 09:00", "2025-02-15 00:00", []string{
+			"2025-01-13 09:00", "2025-01-17 09:00", "2025-01-27 09:00", "2025-01-31 09:00",
+			"2025-02-10 09:00", "2025-02-14 09:00"}},
+		{"biweekly without BYDAY", "FREQ=WEEKLY;INTERVAL=2;COUNT=3", "2025-01-15 09:00", "", []string{
+			"2025-01-15 09:00", "2025-01-29 09:00", "2025-02-12 09:00"}},
+		// RFC 5545 examples: WKST changes the weeks INTERVAL=2 skips
+		{"WKST=MO", "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO", "1997-08-05 09:00", "", []string{
+			"1997-08-05 09:00", "1997-08-10 09:00", "1997-08-19 09:00", "1997-08-24 09:00"}},
+		{"WKST=SU", "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU", "1997-08-05 09:00", "", []string{
+			"1997-08-05 09:00", "1997-08-17 09:00", "1997-08-19 09:00", "1997-08-31 09:00"}},
+		{"weekly across DST", "FREQ=WEEKLY;COUNT=2", "2025-03-03 09:00", "", []string{
+			"2025-03-03 09:00", "2025-03-10 09:00"}},
+		{"second Tuesday", "FREQ=MONTHLY;BYDAY=2TU;COUNT=3", "2025-01-14 14:00", "", []string{
+			"2025-01-14 14:00", "2025-02-11 14:00", "2025-03-11 14:00"}},
+		{"last Friday", "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", "2025-01-31 16:00", "", []string{
+			"2025-01-31 16:00", "2025-02-28 16:00", "2025-03-28 16:00"}},
+		{"last day of month", "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3", "2025-01-31 16:00", "", []string{
+			"2025-01-31 16:00", "2025-02-28 16:00", "2025-03-31 16:00"}},
+		{"monthly on the 31st skips short months", "FREQ=MONTHLY;COUNT=3", "2025-01-31 16:00", "", []string{
+			"2025-01-31 16:00", "2025-03-31 16:00", "2025-05-31 16:00"}},
+		// RFC 5545: every Friday the 13th
+		{"Friday the 13th", "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13;COUNT=3", "2025-01-01 12:00", "", []string{
+			"2025-06-13 12:00", "2026-02-13 12:00", "2026-03-13 12:00"}},
+		{"quarterly", "FREQ=MONTHLY;INTERVAL=3;BYDAY=1MO;COUNT=3", "2025-01-06 10:00", "", []string{
+			"2025-01-06 10:00", "2025-04-07 10:00", "2025-07-07 10:00"}},
+		{"yearly on leap day", "FREQ=YEARLY;COUNT=2", "2024-02-29 10:00", "", []string{
+			"2024-02-29 10:00", "2028-02-29 10:00"}},
+		{"lower case", "freq=weekly;byday=mo;count=2", "2025-01-13 09:00", "", []string{
+			"2025-01-13 09:00", "2025-01-20 09:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := far
+			if tt.end != "" {
+				end = at(tt.end)
+			}
+			starts, err := expandRRULE(tt.rule, at(tt.start), end)
+			if err != nil {
+				t.Fatalf("expandRRULE(%s): %v", tt.rule, err)
+			}
+			got := make([]string, len(starts))
+			for i, s := range starts {
+				got[i] = s.In(ny).Format("2006-01-02 15:04")
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("expandRRULE(%s)\n got %v\nwant %v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandRRULEErrors(t *testing.T) {
+	start := time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=HOURLY", "unsupported RRULE FREQ"},
+		{"COUNT=3", "unsupported RRULE FREQ"},
+		{"FREQ=MONTHLY;BYSETPOS=-1;BYDAY=MO,TU,WE,TH,FR", "unsupported RRULE part BYSETPOS"},
+		{"FREQ=WEEKLY;BYDAY=-1FR", "unsupported RRULE BYDAY"},
+		{"FREQ=WEEKLY;BYMONTHDAY=13", "unsupported RRULE BYMONTHDAY"},
+		{"FREQ=YEARLY;BYDAY=1MO", "unsupported RRULE BYDAY"},
+		{"FREQ=DAILY;INTERVAL=0", "invalid RRULE INTERVAL"},
+		{"FREQ=DAILY;COUNT=-1", "invalid RRULE COUNT"},
+		{"FREQ=DAILY;UNTIL=tomorrow", "invalid RRULE UNTIL"},
+		{"FREQ=WEEKLY;WKST=XX", "invalid RRULE WKST"},
+		{"FREQ=WEEKLY;BYDAY=MX", "invalid RRULE BYDAY"},
+		{"FREQ=MONTHLY;BYDAY=6MO", "invalid RRULE BYDAY"},
+		{"FREQ=MONTHLY;BYMONTHDAY=0", "invalid RRULE BYMONTHDAY"},
+	}
+	for _, tt := range tests {
+		_, err := expandRRULE(tt.rule, start, start.AddDate(1, 0, 0))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expandRRULE(%s) error = %v, want %q", tt.rule, err, tt.want)
+		}
+	}
+}
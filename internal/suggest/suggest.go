@@ -0,0 +1,144 @@
+package suggest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/input"
+	"github.com/user/tcrs/internal/tracker"
+)
+
+// FromCommits proposes import rows from commits. Repositories are mapped
+// to TCRS projects and activities by the git rules of the mapping,
+// matching the repository name or path. Each day's hours (from hoursOn, 0 on days off)
+// are shared between the matched targets by their number of commits and
+// rounded to step, keeping the day's total. Commit subjects become the
+// note. Commits of unmapped repositories are an error.
+func FromCommits(commits []Commit, mapping *tracker.Mapping, hoursOn func(time.Time) float64, step float64) ([]input.SheetRow, error) {
+	type target struct{ project, activity string }
+	type share struct {
+		target   target
+		rule     int
+		commits  int
+		subjects []string
+	}
+	byDay := make(map[string][]*share)
+	unmapped := make(map[string]bool)
+
+	for _, c := range commits {
+		span := tracker.Span{Source: tracker.SourceGit, Project: RepoName(c.Repo), Tags: []string{c.Repo}, Description: c.Subject}
+		idx, rule, ok := mapping.Match(span)
+		if !ok {
+			unmapped[RepoName(c.Repo)] = true
+			continue
+		}
+		day := c.Date.Format(dates.Layout)
+		t := target{rule.Project, rule.Activity}
+		var s *share
+		for _, existing := range byDay[day] {
+			if existing.target == t {
+				s = existing
+				break
+			}
+		}
+		if s == nil {
+			s = &share{target: t, rule: idx}
+			byDay[day] = append(byDay[day], s)
+		}
+		s.commits++
+		s.subjects = tracker.AddNote(s.subjects, c.Subject)
+	}
+
+	if len(unmapped) > 0 {
+		names := make([]string, 0, len(unmapped))
+		for n := range unmapped {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("no mapping rule matches repositories %s", strings.Join(names, ", "))
+	}
+
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	var entries []tracker.Entry
+	for _, day := range days {
+		date, _ := time.Parse(dates.Layout, day)
+		shares := byDay[day]
+		weights := make([]int, len(shares))
+		for i, s := range shares {
+			weights[i] = s.commits
+		}
+		hours := distribute(hoursOn(date), weights, step)
+		for i, s := range shares {
+			entries = append(entries, tracker.Entry{
+				Date:     date,
+				Project:  s.target.project,
+				Activity: s.target.activity,
+				Hours:    hours[i],
+				Note:     strings.Join(s.subjects, "; "),
+				Source:   fmt.Sprintf("%s, mapping rule %d", day, s.rule+1),
+			})
+		}
+	}
+	// Hours are rounded by distribute already
+	return tracker.SumRows(entries, tracker.Rounding{}), nil
+}
+
+// distribute splits total between parts by weight in whole steps, using
+// largest remainders: every part gets the steps its share covers, and the
+// steps left over go to the parts with the largest remainders, so no part
+// is negative and the parts add up to the rounded total.
+func distribute(total float64, weights []int, step float64) []float64 {
+	parts := make([]float64, len(weights))
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+	if total <= 0 || sum <= 0 {
+		return parts
+	}
+	if step <= 0 {
+		for i, w := range weights {
+			parts[i] = total * float64(w) / float64(sum)
+		}
+		return parts
+	}
+
+	steps := int(math.Round(total / step))
+	units := make([]int, len(weights))
+	remainders := make([]float64, len(weights))
+	left := steps
+	for i, w := range weights {
+		quota := float64(steps) * float64(w) / float64(sum)
+		units[i] = int(quota)
+		remainders[i] = quota - float64(units[i])
+		left -= units[i]
+	}
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	// Ties go to the heavier part, then to the earlier one
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if remainders[i] != remainders[j] {
+			return remainders[i] > remainders[j]
+		}
+		return weights[i] > weights[j]
+	})
+	for _, i := range order[:left] {
+		units[i]++
+	}
+	for i, u := range units {
+		parts[i] = float64(u) * step
+	}
+	return parts
+}
@@ -0,0 +1,57 @@
+package suggest
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDistribute(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   float64
+		weights []int
+		step    float64
+		want    []float64
+	}{
+		{"proportional", 8, []int{3, 1}, 0.25, []float64{6, 2}},
+		{"remainder to largest fraction", 8, []int{1, 1, 1}, 0.5, []float64{3, 2.5, 2.5}},
+		{"heavier part wins a tie", 1, []int{1, 3, 1, 3}, 0.5, []float64{0, 0.5, 0, 0.5}},
+		{"fewer steps than parts", 0.875, []int{1, 1, 1, 1, 1, 1, 1}, 0.25, []float64{0.25, 0.25, 0.25, 0.25, 0, 0, 0}},
+		{"total rounded to step", 7.9, []int{1, 1}, 0.5, []float64{4, 4}},
+		{"no step", 3, []int{1, 2}, 0, []float64{1, 2}},
+		{"no hours", 0, []int{1, 2}, 0.25, []float64{0, 0}},
+		{"no weight", 8, []int{0, 0}, 0.25, []float64{0, 0}},
+		{"no parts", 8, nil, 0.25, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := distribute(tt.total, tt.weights, tt.step)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("distribute(%v, %v, %v) = %v, want %v", tt.total, tt.weights, tt.step, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistributeSumsToRoundedTotal(t *testing.T) {
+	const step = 0.25
+	for n := 1; n <= 12; n++ {
+		weights := make([]int, n)
+		for i := range weights {
+			weights[i] = i%4 + 1
+		}
+		for total := 0.125; total <= 12; total += 0.375 {
+			sum := 0.0
+			for _, h := range distribute(total, weights, step) {
+				if h < 0 {
+					t.Fatalf("distribute(%v, %v) has a negative part %v", total, weights, h)
+				}
+				sum += h
+			}
+			if want := math.Round(total/step) * step; math.Abs(sum-want) > 1e-9 {
+				t.Errorf("distribute(%v, %v) sums to %v, want %v", total, weights, sum, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package textwidth measures, truncates and pads text by terminal
+// columns, counting East Asian wide characters as two, so that tables of
+// Chinese project names line up.
+package textwidth
+
+import (
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// Width returns the number of terminal columns s takes.
+func Width(s string) int {
+	w := 0
+	for _, r := range s {
+		w += RuneWidth(r)
+	}
+	return w
+}
+
+// RuneWidth returns the number of terminal columns r takes: two for East
+// Asian wide and fullwidth characters, one otherwise.
+func RuneWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// Truncate shortens s to at most w columns, ending it with tail (e.g.
+// "...") when it is cut. Characters are never split.
+func Truncate(s string, w int, tail string) string {
+	if Width(s) <= w {
+		return s
+	}
+	room := w - Width(tail)
+	if room < 0 {
+		return ""
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		if used+RuneWidth(r) > room {
+			break
+		}
+		b.WriteRune(r)
+		used += RuneWidth(r)
+	}
+	return b.String() + tail
+}
+
+// Pad fills s with spaces on the right up to w columns.
+func Pad(s string, w int) string {
+	if n := w - Width(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeft fills s with spaces on the left up to w columns.
+func PadLeft(s string, w int) string {
+	if n := w - Width(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
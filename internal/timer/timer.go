@@ -0,0 +1,168 @@
+// Package timer keeps a local start/stop timer: the running interval and
+// the completed ones, stored as JSON, and sums them up per day for TCRS.
+package timer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/input"
+	"github.com/user/tcrs/internal/tracker"
+)
+
+// ErrNotRunning is returned when stopping a timer that is not running.
+var ErrNotRunning = errors.New("no timer is running")
+
+// Interval is time spent on a project and activity. Project and activity
+// are IDs or names, resolved like imported sheet rows when pushed. End
+// is zero while the timer runs.
+type Interval struct {
+	Project  string    `json:"project"`
+	Activity string    `json:"activity"`
+	Note     string    `json:"note,omitempty"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+// Duration returns the length of the interval, up to now if it is still
+// running.
+func (iv Interval) Duration(now time.Time) time.Duration {
+	end := iv.End
+	if end.IsZero() {
+		end = now
+	}
+	return end.Sub(iv.Start)
+}
+
+// Store is the timer state: the running interval, if any, and the
+// completed intervals in start order.
+type Store struct {
+	Running   *Interval  `json:"running,omitempty"`
+	Intervals []Interval `json:"intervals"`
+}
+
+// Load reads a timer store. A missing file is an empty store.
+func Load(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Store{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Store
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse JSON: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the store to path, creating its directory if needed.
+func (s *Store) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Start starts a timer at now. A timer already running is stopped first
+// and returned.
+func (s *Store) Start(project, activity, note string, now time.Time) *Interval {
+	stopped, _ := s.Stop(now)
+	s.Running = &Interval{Project: project, Activity: activity, Note: note, Start: now}
+	return stopped
+}
+
+// Stop stops the running timer at now and returns the completed interval.
+func (s *Store) Stop(now time.Time) (*Interval, error) {
+	if s.Running == nil {
+		return nil, ErrNotRunning
+	}
+	iv := *s.Running
+	iv.End = now
+	if iv.End.Before(iv.Start) {
+		iv.End = iv.Start
+	}
+	s.Running = nil
+	s.Intervals = append(s.Intervals, iv)
+	sort.SliceStable(s.Intervals, func(i, j int) bool {
+		return s.Intervals[i].Start.Before(s.Intervals[j].Start)
+	})
+	return &iv, nil
+}
+
+// Total returns the time tracked between from and to (inclusive days,
+// local time), counting the running timer up to now.
+func (s *Store) Total(from, to, now time.Time) time.Duration {
+	var total time.Duration
+	for _, part := range s.parts(from, to, now, true) {
+		total += part.End.Sub(part.Start)
+	}
+	return total
+}
+
+// Rows sums the completed intervals between from and to (inclusive days,
+// local time) per day, project and activity, rounds each sum and returns
+// them as import rows in date order. Distinct notes are joined.
+func (s *Store) Rows(from, to time.Time, rounding tracker.Rounding) []input.SheetRow {
+	parts := s.parts(from, to, time.Time{}, false)
+	entries := make([]tracker.Entry, 0, len(parts))
+	for _, part := range parts {
+		date := part.Start.Format(dates.Layout)
+		entries = append(entries, tracker.Entry{
+			Date:     part.Start,
+			Project:  part.Project,
+			Activity: part.Activity,
+			Hours:    part.End.Sub(part.Start).Hours(),
+			Note:     part.Note,
+			Source:   fmt.Sprintf("timer %s %s/%s", date, part.Project, part.Activity),
+		})
+	}
+	return tracker.SumRows(entries, rounding)
+}
+
+// parts returns the intervals between from and to split at local
+// midnight, so that each part lies within one day.
+func (s *Store) parts(from, to, now time.Time, running bool) []Interval {
+	rangeStart := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	rangeEnd := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, time.Local)
+
+	intervals := s.Intervals
+	if running && s.Running != nil {
+		iv := *s.Running
+		iv.End = now
+		intervals = append(append([]Interval{}, intervals...), iv)
+	}
+
+	var parts []Interval
+	for _, iv := range intervals {
+		start, end := iv.Start.Local(), iv.End.Local()
+		if start.Before(rangeStart) {
+			start = rangeStart
+		}
+		if end.After(rangeEnd) {
+			end = rangeEnd
+		}
+		for start.Before(end) {
+			midnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, time.Local)
+			part := iv
+			part.Start, part.End = start, end
+			if part.End.After(midnight) {
+				part.End = midnight
+			}
+			parts = append(parts, part)
+			start = part.End
+		}
+	}
+	return parts
+}
@@ -0,0 +1,131 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeaders are the header names Toggl and Clockify use for each field,
+// lower-cased, in order of preference.
+var csvHeaders = map[string][]string{
+	"project":     {"project"},
+	"description": {"description"},
+	"tags":        {"tags"},
+	"date":        {"start date"},
+	"hours":       {"duration (decimal)"},
+	"duration":    {"duration", "duration (h)"},
+}
+
+// dateLayouts are the start date formats found in Toggl and Clockify
+// exports.
+var dateLayouts = []string{"2006-01-02", "01/02/2006", "2006/01/02", "02.01.2006", "1/2/2006"}
+
+// parseCSV parses a Toggl or Clockify detailed report CSV export.
+func parseCSV(data []byte) ([]Span, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel-style BOM
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := make(map[string]int)
+	for i, name := range records[0] {
+		header[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	columns := make(map[string]int)
+	for field, names := range csvHeaders {
+		for _, name := range names {
+			if idx, ok := header[name]; ok {
+				columns[field] = idx
+				break
+			}
+		}
+	}
+	if _, ok := columns["date"]; !ok {
+		return nil, fmt.Errorf("CSV header has no %q column; is this a detailed report export?", "Start date")
+	}
+	_, hasHours := columns["hours"]
+	_, hasDuration := columns["duration"]
+	if !hasHours && !hasDuration {
+		return nil, fmt.Errorf("CSV header has no %q column", "Duration")
+	}
+
+	spans := make([]Span, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		line := i + 2
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[idx])
+		}
+		if field("date") == "" {
+			continue
+		}
+
+		date, err := parseDate(field("date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		var hours float64
+		if hasHours {
+			hours, err = strconv.ParseFloat(field("hours"), 64)
+		} else {
+			hours, err = parseClock(field("duration"))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid duration: %w", line, err)
+		}
+
+		var tags []string
+		for _, tag := range strings.Split(field("tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		spans = append(spans, Span{
+			Date:        date,
+			Hours:       hours,
+			Project:     field("project"),
+			Tags:        tags,
+			Description: field("description"),
+		})
+	}
+	return spans, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// parseClock parses an H:MM:SS or H:MM duration into hours.
+func parseClock(s string) (float64, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("%q is not H:MM:SS", s)
+	}
+	hours := 0.0
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%q is not H:MM:SS", s)
+		}
+		hours += float64(n) / []float64{1, 60, 3600}[i]
+	}
+	return hours, nil
+}
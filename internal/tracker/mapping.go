@@ -0,0 +1,114 @@
+package tracker
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of spans a rule can apply to.
+const (
+	SourceTracker = "tracker" // time-tracker exports
+	SourceGit     = "git"     // commits, by repository name
+	SourceICS     = "ics"     // calendar meetings
+)
+
+// Rule maps tracked time to a TCRS project and activity. It applies only
+// to spans of its Source, by default SourceTracker. Match compares
+// case-insensitively against a span's project and each of its tags;
+// Contains looks for a substring of its description. A rule with both
+// needs both to match. Project and activity are IDs or names, resolved
+// like imported sheet rows.
+type Rule struct {
+	Source   string `yaml:"source"`
+	Match    string `yaml:"match"`
+	Contains string `yaml:"contains"`
+	Project  string `yaml:"project"`
+	Activity string `yaml:"activity"`
+}
+
+// Mapping is an ordered list of rules; the first matching rule of a
+// span's source wins.
+//
+//	rules:
+//	  - match: backend
+//	    project: "12345"
+//	    activity: Development
+//	  - source: git
+//	    match: billing-service
+//	    project: "12345"
+//	    activity: Development
+//	  - source: ics
+//	    contains: standup
+//	    project: Internal
+//	    activity: Meeting
+type Mapping struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadMapping reads a YAML mapping file.
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Mapping
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse YAML: %w", path, err)
+	}
+	for i, r := range m.Rules {
+		if r.Project == "" {
+			return nil, fmt.Errorf("%s: rule %d has no project", path, i+1)
+		}
+		if r.Match == "" && r.Contains == "" {
+			return nil, fmt.Errorf("%s: rule %d needs match or contains", path, i+1)
+		}
+		switch r.Source {
+		case "", SourceTracker, SourceGit, SourceICS:
+		default:
+			return nil, fmt.Errorf("%s: rule %d has unknown source %q (use tracker, git or ics)", path, i+1, r.Source)
+		}
+	}
+	return &m, nil
+}
+
+// Match returns the index and rule of the first rule of span's source
+// matching span.
+func (m *Mapping) Match(span Span) (int, Rule, bool) {
+	for i, r := range m.Rules {
+		if r.matches(span) {
+			return i, r, true
+		}
+	}
+	return -1, Rule{}, false
+}
+
+func (r Rule) matches(span Span) bool {
+	if sourceOf(r.Source) != sourceOf(span.Source) {
+		return false
+	}
+	if r.Match != "" {
+		found := strings.EqualFold(span.Project, r.Match)
+		for _, tag := range span.Tags {
+			found = found || strings.EqualFold(tag, r.Match)
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Contains != "" && !strings.Contains(strings.ToLower(span.Description), strings.ToLower(r.Contains)) {
+		return false
+	}
+	return true
+}
+
+// sourceOf returns the source a rule or span belongs to; none means a
+// time-tracker export.
+func sourceOf(source string) string {
+	if source == "" {
+		return SourceTracker
+	}
+	return source
+}
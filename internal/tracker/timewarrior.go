@@ -0,0 +1,65 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// twInterval is an interval of `timew export`.
+type twInterval struct {
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Tags       []string `json:"tags"`
+	Annotation string   `json:"annotation"`
+}
+
+// twLayout is the UTC timestamp format of Timewarrior exports.
+const twLayout = "20060102T150405Z"
+
+// parseTimewarrior parses `timew export` JSON. Intervals are assigned to
+// days in loc and split at midnight; open intervals are skipped. The first
+// tag doubles as the project.
+func parseTimewarrior(data []byte, loc *time.Location) ([]Span, error) {
+	var intervals []twInterval
+	if err := json.Unmarshal(data, &intervals); err != nil {
+		return nil, fmt.Errorf("failed to parse Timewarrior JSON: %w", err)
+	}
+
+	var spans []Span
+	for i, iv := range intervals {
+		if iv.End == "" {
+			continue // still running
+		}
+		start, err := time.Parse(twLayout, iv.Start)
+		if err != nil {
+			return nil, fmt.Errorf("interval %d: invalid start %q", i+1, iv.Start)
+		}
+		end, err := time.Parse(twLayout, iv.End)
+		if err != nil {
+			return nil, fmt.Errorf("interval %d: invalid end %q", i+1, iv.End)
+		}
+		start, end = start.In(loc), end.In(loc)
+
+		project := ""
+		if len(iv.Tags) > 0 {
+			project = iv.Tags[0]
+		}
+		for start.Before(end) {
+			midnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, loc)
+			stop := end
+			if midnight.Before(stop) {
+				stop = midnight
+			}
+			spans = append(spans, Span{
+				Date:        time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+				Hours:       stop.Sub(start).Hours(),
+				Project:     project,
+				Tags:        iv.Tags,
+				Description: iv.Annotation,
+			})
+			start = stop
+		}
+	}
+	return spans, nil
+}
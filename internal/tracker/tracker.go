@@ -0,0 +1,217 @@
+// Package tracker reads time-tracker exports (Toggl and Clockify CSV,
+// Timewarrior JSON), maps their projects and tags to TCRS projects and
+// activities, and sums them up per day.
+package tracker
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/tcrs/internal/client"
+	"github.com/user/tcrs/internal/dates"
+	"github.com/user/tcrs/internal/input"
+)
+
+// Supported tracker formats.
+const (
+	FormatToggl       = "toggl"
+	FormatClockify    = "clockify"
+	FormatTimewarrior = "timewarrior"
+)
+
+// IsFormat reports whether format is a tracker format.
+func IsFormat(format string) bool {
+	switch format {
+	case FormatToggl, FormatClockify, FormatTimewarrior:
+		return true
+	}
+	return false
+}
+
+// Span is a stretch of tracked time within a single day.
+type Span struct {
+	Source      string    // SourceTracker (or ""), SourceGit or SourceICS
+	Date        time.Time // day the time was spent, at midnight UTC
+	Hours       float64
+	Project     string
+	Tags        []string
+	Description string
+}
+
+// Parse parses a tracker export in the given format.
+func Parse(data []byte, format string) ([]Span, error) {
+	switch format {
+	case FormatToggl, FormatClockify:
+		return parseCSV(data)
+	case FormatTimewarrior:
+		return parseTimewarrior(data, time.Local)
+	default:
+		return nil, fmt.Errorf("unknown tracker format %q (use toggl, clockify or timewarrior)", format)
+	}
+}
+
+// Rounding modes.
+const (
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+)
+
+// Rounding rounds daily hours to a multiple of Step; a zero Step keeps
+// hours as they are.
+type Rounding struct {
+	Step float64
+	Mode string // RoundNearest (default), RoundUp or RoundDown
+}
+
+// Round rounds hours according to r.
+func (r Rounding) Round(hours float64) float64 {
+	if r.Step <= 0 {
+		return hours
+	}
+	n := hours / r.Step
+	switch r.Mode {
+	case RoundUp:
+		n = math.Ceil(n - 1e-9)
+	case RoundDown:
+		n = math.Floor(n + 1e-9)
+	case RoundNearest, "":
+		n = math.Round(n)
+	default:
+		return hours
+	}
+	return n * r.Step
+}
+
+// Validate checks the rounding mode.
+func (r Rounding) Validate() error {
+	switch r.Mode {
+	case RoundNearest, RoundUp, RoundDown, "":
+		return nil
+	}
+	return fmt.Errorf("unknown rounding mode %q (use nearest, up or down)", r.Mode)
+}
+
+// Rows maps spans to TCRS projects and activities with the mapping,
+// sums their hours per day and target, rounds each sum and returns them
+// as import rows in date order. Descriptions become the note. Spans that
+// no rule matches are an error naming their projects and tags.
+func Rows(spans []Span, mapping *Mapping, rounding Rounding) ([]input.SheetRow, error) {
+	entries := make([]Entry, 0, len(spans))
+	unmapped := make(map[string]bool)
+	for _, span := range spans {
+		idx, rule, ok := mapping.Match(span)
+		if !ok {
+			unmapped[spanLabel(span)] = true
+			continue
+		}
+		entries = append(entries, Entry{
+			Date:     span.Date,
+			Project:  rule.Project,
+			Activity: rule.Activity,
+			Hours:    span.Hours,
+			Note:     span.Description,
+			Source:   fmt.Sprintf("%s, mapping rule %d", span.Date.Format(dates.Layout), idx+1),
+		})
+	}
+
+	if len(unmapped) > 0 {
+		labels := make([]string, 0, len(unmapped))
+		for l := range unmapped {
+			labels = append(labels, l)
+		}
+		sort.Strings(labels)
+		return nil, fmt.Errorf("no mapping rule matches %s", strings.Join(labels, ", "))
+	}
+	return SumRows(entries, rounding), nil
+}
+
+// Entry is time spent on a TCRS project and activity on one day.
+type Entry struct {
+	Date     time.Time // only the date counts
+	Project  string
+	Activity string
+	Hours    float64
+	Note     string
+	Source   string // names the row in errors; the first entry's is kept
+}
+
+// SumRows sums entries per day, project and activity, rounds each sum
+// and returns them as import rows in date order. Distinct notes are
+// joined with "; ". Sums that round to nothing are left out.
+func SumRows(entries []Entry, rounding Rounding) []input.SheetRow {
+	type key struct {
+		date              string
+		project, activity string
+	}
+	type sum struct {
+		hours  float64
+		notes  []string
+		source string
+	}
+	sums := make(map[key]*sum)
+	var order []key
+	for _, e := range entries {
+		k := key{e.Date.Format(dates.Layout), e.Project, e.Activity}
+		s, ok := sums[k]
+		if !ok {
+			s = &sum{source: e.Source}
+			sums[k] = s
+			order = append(order, k)
+		}
+		s.hours += e.Hours
+		s.notes = AddNote(s.notes, e.Note)
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i].date < order[j].date })
+	rows := make([]input.SheetRow, 0, len(order))
+	for _, k := range order {
+		s := sums[k]
+		hours := rounding.Round(s.hours)
+		if hours <= 0 {
+			continue
+		}
+		date, _ := time.Parse(dates.Layout, k.date)
+		rows = append(rows, input.SheetRow{
+			Source:   s.source,
+			Date:     date,
+			Project:  k.project,
+			Activity: k.activity,
+			Hours:    client.NewHours(hours),
+			Note:     strings.Join(s.notes, "; "),
+		})
+	}
+	return rows
+}
+
+// AddNote appends note to notes unless it is blank or already there.
+func AddNote(notes []string, note string) []string {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return notes
+	}
+	for _, n := range notes {
+		if n == note {
+			return notes
+		}
+	}
+	return append(notes, note)
+}
+
+// spanLabel names a span's project and tags for error messages.
+func spanLabel(span Span) string {
+	parts := make([]string, 0, 1+len(span.Tags))
+	if span.Project != "" {
+		parts = append(parts, "project "+fmt.Sprintf("%q", span.Project))
+	}
+	for _, tag := range span.Tags {
+		parts = append(parts, "tag "+fmt.Sprintf("%q", tag))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%q", span.Description)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
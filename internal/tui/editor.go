@@ -0,0 +1,444 @@
+// Package tui is a full-screen terminal editor for a week's timecard,
+// drawn with plain ANSI escape sequences.
+package tui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/user/tcrs/internal/client"
+	"golang.org/x/term"
+)
+
+// Row is a project/activity row of the grid.
+type Row struct {
+	ProjectName  string
+	ActivityName string
+	Entry        client.SaveEntry
+}
+
+// label names the row's project and activity.
+func (r Row) label() string {
+	if r.ActivityName == "" {
+		return r.ProjectName
+	}
+	return r.ProjectName + " / " + r.ActivityName
+}
+
+// Choice is a project/activity that can be added as a row.
+type Choice struct {
+	ProjectID    string
+	ProjectName  string
+	ActivityID   string
+	ActivityName string
+	WBS          string
+}
+
+func (c Choice) label() string {
+	if c.ActivityName == "" {
+		return c.ProjectName
+	}
+	return c.ProjectName + " / " + c.ActivityName
+}
+
+// Week is the timecard the editor works on.
+type Week struct {
+	StartDate string
+	Status    string
+	Labels    [7]string // column headers, e.g. "Mon 10/12"
+	Rows      []Row
+	Choices   []Choice
+	// Validate checks the entries before the save confirmation.
+	Validate func([]client.SaveEntry) error
+}
+
+type mode int
+
+const (
+	modeGrid mode = iota
+	modeHours
+	modeNote
+	modePicker
+	modeConfirm
+	modeDiscard
+)
+
+// editor is the state of a running editor.
+type editor struct {
+	week     Week
+	rows     []Row
+	original []client.SaveEntry
+
+	mode     mode
+	row, col int
+	top      int // first row shown
+	input    []rune
+	message  string
+
+	query   []rune
+	matches []Choice
+	pick    int
+
+	changes []client.DayChange
+	width   int
+	height  int
+}
+
+// Run opens the editor full-screen on the terminal in and out. It
+// returns the edited entries once the user confirms saving them, or ok
+// false if they quit.
+func Run(in, out *os.File, week Week) (entries []client.SaveEntry, ok bool, err error) {
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	e := newEditor(week)
+	buf := make([]byte, 256)
+	for {
+		e.width, e.height, err = term.GetSize(int(out.Fd()))
+		if err != nil {
+			e.width, e.height = 100, 30
+		}
+		fmt.Fprint(out, "\x1b[H\x1b[2J"+strings.Join(e.render(), "\r\n"))
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			switch e.handle(k) {
+			case resultSave:
+				return e.entries(), true, nil
+			case resultQuit:
+				return nil, false, nil
+			}
+		}
+	}
+}
+
+func newEditor(week Week) *editor {
+	e := &editor{week: week}
+	for _, r := range week.Rows {
+		e.rows = append(e.rows, normalizeRow(r))
+	}
+	e.original = e.entries()
+	return e
+}
+
+// normalizeRow gives a row exactly seven days.
+func normalizeRow(r Row) Row {
+	days := make([]client.SaveDayEntry, 7)
+	copy(days, r.Entry.Days)
+	r.Entry.Days = days
+	return r
+}
+
+// entries returns the rows as entries to save, leaving out empty rows.
+func (e *editor) entries() []client.SaveEntry {
+	entries := make([]client.SaveEntry, 0, len(e.rows))
+	for _, r := range e.rows {
+		empty := true
+		for _, d := range r.Entry.Days {
+			if !d.Hours.IsEmpty() || strings.TrimSpace(d.Note) != "" {
+				empty = false
+			}
+		}
+		if !empty {
+			entry := r.Entry
+			entry.Days = append([]client.SaveDayEntry(nil), r.Entry.Days...)
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+type result int
+
+const (
+	resultNone result = iota
+	resultSave
+	resultQuit
+)
+
+// handle applies a key press.
+func (e *editor) handle(k key) result {
+	switch e.mode {
+	case modeHours, modeNote:
+		e.handleInput(k)
+	case modePicker:
+		e.handlePicker(k)
+	case modeConfirm:
+		switch {
+		case k.code == keyRune && (k.r == 'y' || k.r == 'Y'):
+			return resultSave
+		case k.code == keyRune && (k.r == 'n' || k.r == 'N'), k.code == keyEsc, k.code == keyCtrlC:
+			e.mode = modeGrid
+		}
+	case modeDiscard:
+		switch {
+		case k.code == keyRune && (k.r == 'y' || k.r == 'Y'), k.code == keyCtrlC:
+			return resultQuit
+		case k.code == keyRune && (k.r == 'n' || k.r == 'N'), k.code == keyEsc:
+			e.mode = modeGrid
+		}
+	default:
+		return e.handleGrid(k)
+	}
+	return resultNone
+}
+
+func (e *editor) handleGrid(k key) result {
+	e.message = ""
+	switch k.code {
+	case keyUp:
+		e.move(-1, 0)
+	case keyDown:
+		e.move(1, 0)
+	case keyLeft, keyBacktab:
+		e.move(0, -1)
+	case keyRight, keyTab:
+		e.move(0, 1)
+	case keyHome:
+		e.col = 0
+	case keyEnd:
+		e.col = 6
+	case keyPageUp:
+		e.move(-e.visibleRows(), 0)
+	case keyPageDown:
+		e.move(e.visibleRows(), 0)
+	case keyEnter:
+		if day := e.day(); day != nil {
+			e.startInput(modeHours, day.Hours.String())
+		}
+	case keyBackspace, keyDelete:
+		if day := e.day(); day != nil {
+			day.Hours = client.Hours{}
+		}
+	case keyEsc, keyCtrlC:
+		return e.quit()
+	case keyRune:
+		switch r := k.r; {
+		case r >= '0' && r <= '9', r == '.':
+			if e.day() != nil {
+				e.startInput(modeHours, string(r))
+			}
+		case r == 'k':
+			e.move(-1, 0)
+		case r == 'j':
+			e.move(1, 0)
+		case r == 'h':
+			e.move(0, -1)
+		case r == 'l':
+			e.move(0, 1)
+		case r == 'n':
+			if day := e.day(); day != nil {
+				e.startInput(modeNote, day.Note)
+			}
+		case r == 'a':
+			e.mode = modePicker
+			e.query = nil
+			e.filter()
+		case r == 'x':
+			if len(e.rows) > 0 {
+				e.message = "Removed " + e.rows[e.row].label()
+				e.rows = append(e.rows[:e.row], e.rows[e.row+1:]...)
+				e.move(0, 0)
+			}
+		case r == 's':
+			e.save()
+		case r == 'q':
+			return e.quit()
+		}
+	}
+	return resultNone
+}
+
+// quit leaves at once if nothing changed, or asks first.
+func (e *editor) quit() result {
+	if len(client.DiffEntries(e.original, e.entries())) == 0 {
+		return resultQuit
+	}
+	e.mode = modeDiscard
+	return resultNone
+}
+
+// save validates the entries and shows the changes for confirmation.
+func (e *editor) save() {
+	entries := e.entries()
+	e.changes = client.DiffEntries(e.original, entries)
+	if len(e.changes) == 0 {
+		e.message = "No changes to save"
+		return
+	}
+	if e.week.Validate != nil {
+		if err := e.week.Validate(entries); err != nil {
+			e.message = "Cannot save: " + err.Error()
+			return
+		}
+	}
+	e.mode = modeConfirm
+}
+
+func (e *editor) move(dRow, dCol int) {
+	e.row += dRow
+	if e.row >= len(e.rows) {
+		e.row = len(e.rows) - 1
+	}
+	if e.row < 0 {
+		e.row = 0
+	}
+	e.col += dCol
+	if e.col > 6 {
+		e.col = 6
+	}
+	if e.col < 0 {
+		e.col = 0
+	}
+}
+
+// day returns the cell under the cursor, or nil if there are no rows.
+func (e *editor) day() *client.SaveDayEntry {
+	if e.row >= len(e.rows) {
+		return nil
+	}
+	return &e.rows[e.row].Entry.Days[e.col]
+}
+
+func (e *editor) startInput(m mode, initial string) {
+	e.mode = m
+	e.input = []rune(initial)
+}
+
+// handleInput edits the hours or note of the current cell. Enter and the
+// arrow keys keep the value, Escape drops it.
+func (e *editor) handleInput(k key) {
+	switch k.code {
+	case keyRune:
+		if e.mode == modeHours && !isHoursRune(k.r) {
+			return
+		}
+		e.input = append(e.input, k.r)
+	case keyBackspace:
+		if len(e.input) > 0 {
+			e.input = e.input[:len(e.input)-1]
+		}
+	case keyEsc, keyCtrlC:
+		e.mode = modeGrid
+	case keyEnter, keyTab, keyBacktab, keyUp, keyDown:
+		if !e.commitInput() {
+			return
+		}
+		e.mode = modeGrid
+		switch k.code {
+		case keyTab:
+			e.move(0, 1)
+		case keyBacktab:
+			e.move(0, -1)
+		case keyUp:
+			e.move(-1, 0)
+		case keyDown:
+			e.move(1, 0)
+		}
+	}
+}
+
+// isHoursRune reports whether r can be part of hours as ParseHours
+// reads them: 7.5, 7:30 or 7h30m.
+func isHoursRune(r rune) bool {
+	return r >= '0' && r <= '9' || r == '.' || r == ':' || r == 'h' || r == 'm'
+}
+
+// commitInput stores the input in the current cell.
+func (e *editor) commitInput() bool {
+	day := e.day()
+	if day == nil {
+		return true
+	}
+	if e.mode == modeNote {
+		day.Note = strings.TrimSpace(string(e.input))
+		return true
+	}
+	hours, err := client.ParseHours(string(e.input))
+	if err != nil {
+		e.message = err.Error()
+		return false
+	}
+	day.Hours = hours
+	e.message = ""
+	return true
+}
+
+// handlePicker searches the projects and activities to add as a row.
+func (e *editor) handlePicker(k key) {
+	switch k.code {
+	case keyRune:
+		e.query = append(e.query, k.r)
+		e.filter()
+	case keyBackspace:
+		if len(e.query) > 0 {
+			e.query = e.query[:len(e.query)-1]
+			e.filter()
+		}
+	case keyUp:
+		if e.pick > 0 {
+			e.pick--
+		}
+	case keyDown:
+		if e.pick < len(e.matches)-1 {
+			e.pick++
+		}
+	case keyEsc, keyCtrlC:
+		e.mode = modeGrid
+	case keyEnter:
+		if len(e.matches) == 0 {
+			return
+		}
+		e.addRow(e.matches[e.pick])
+		e.mode = modeGrid
+	}
+}
+
+// filter lists the choices matching every word of the query.
+func (e *editor) filter() {
+	words := strings.Fields(strings.ToLower(string(e.query)))
+	e.matches = e.matches[:0]
+	for _, c := range e.week.Choices {
+		text := strings.ToLower(strings.Join([]string{c.ProjectID, c.ProjectName, c.ActivityID, c.ActivityName, c.WBS}, " "))
+		matched := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			e.matches = append(e.matches, c)
+		}
+	}
+	sort.SliceStable(e.matches, func(i, j int) bool {
+		return e.matches[i].label() < e.matches[j].label()
+	})
+	e.pick = 0
+}
+
+// addRow adds a row for the choice, or moves to it if it is there.
+func (e *editor) addRow(c Choice) {
+	for i, r := range e.rows {
+		if r.Entry.ProjectID == c.ProjectID && r.Entry.ActivityID == c.ActivityID {
+			e.row = i
+			return
+		}
+	}
+	e.rows = append(e.rows, normalizeRow(Row{
+		ProjectName:  c.ProjectName,
+		ActivityName: c.ActivityName,
+		Entry:        client.SaveEntry{ProjectID: c.ProjectID, ActivityID: c.ActivityID},
+	}))
+	e.row = len(e.rows) - 1
+}
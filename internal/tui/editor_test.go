@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/user/tcrs/internal/client"
+)
+
+func testWeek() Week {
+	return Week{
+		StartDate: "2025-01-13",
+		Rows: []Row{{
+			ProjectName:  "Alpha",
+			ActivityName: "Development",
+			Entry: client.SaveEntry{ProjectID: "1", ActivityID: "10", Days: []client.SaveDayEntry{
+				{Hours: client.NewHours(8)},
+			}},
+		}},
+		Choices: []Choice{
+			{ProjectID: "1", ProjectName: "Alpha", ActivityID: "10", ActivityName: "Development"},
+			{ProjectID: "2", ProjectName: "Beta", ActivityID: "20", ActivityName: "Support", WBS: "B-1"},
+		},
+	}
+}
+
+// press feeds the bytes to the editor as one terminal read and returns
+// the result of the last key.
+func press(e *editor, b string) result {
+	res := resultNone
+	for _, k := range parseKeys([]byte(b)) {
+		res = e.handle(k)
+	}
+	return res
+}
+
+func TestHandleHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    string
+		want    [7]string
+		mode    mode
+		message string
+	}{
+		{"decimal", "\x1b[C7.5\r", [7]string{"8", "7.5"}, modeGrid, ""},
+		{"clock", "\x1b[C7:30\r", [7]string{"8", "7.5"}, modeGrid, ""},
+		{"duration", "\x1b[C7h30m\r", [7]string{"8", "7.5"}, modeGrid, ""},
+		{"other characters ignored", "\x1b[C7x-\r", [7]string{"8", "7"}, modeGrid, ""},
+		{"enter edits the cell", "\r\x7f6\r", [7]string{"6"}, modeGrid, ""},
+		{"tab moves on", "\x1b[C4\t2\t", [7]string{"8", "4", "2"}, modeGrid, ""},
+		{"escape cancels", "\x1b[C5\x1b", [7]string{"8"}, modeGrid, ""},
+		{"backspace clears", "\x7f", [7]string{}, modeGrid, ""},
+		{"h moves left", "ll4\rh3\r", [7]string{"8", "3", "4"}, modeGrid, ""},
+		{"invalid stays in the cell", "\x1b[C7:3\r", [7]string{"8"}, modeHours, `invalid hours "7:3" (expected H:MM)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEditor(testWeek())
+			press(e, tt.keys)
+			var got [7]string
+			for d, day := range e.rows[0].Entry.Days {
+				got[d] = day.Hours.String()
+			}
+			if got != tt.want {
+				t.Errorf("hours = %q, want %q", got, tt.want)
+			}
+			if e.mode != tt.mode || e.message != tt.message {
+				t.Errorf("mode, message = %v, %q, want %v, %q", e.mode, e.message, tt.mode, tt.message)
+			}
+		})
+	}
+}
+
+func TestHandleNote(t *testing.T) {
+	e := newEditor(testWeek())
+	press(e, "nreview 7h\r")
+	if got := e.rows[0].Entry.Days[0].Note; got != "review 7h" {
+		t.Errorf("note = %q, want %q", got, "review 7h")
+	}
+}
+
+func TestHandleRows(t *testing.T) {
+	e := newEditor(testWeek())
+	press(e, "asupport\r")
+	if len(e.rows) != 2 || e.rows[1].label() != "Beta / Support" || e.row != 1 {
+		t.Fatalf("rows = %v, row %d, want Beta / Support added and selected", e.rows, e.row)
+	}
+	press(e, "adev\r")
+	if len(e.rows) != 2 || e.row != 0 {
+		t.Errorf("adding an existing row: %d rows, row %d, want 2 rows, row 0", len(e.rows), e.row)
+	}
+	press(e, "x")
+	if len(e.rows) != 1 || e.rows[0].label() != "Beta / Support" || e.message != "Removed Alpha / Development" {
+		t.Errorf("rows = %v, message %q, want Alpha / Development removed", e.rows, e.message)
+	}
+	press(e, "anothing\x1b")
+	if e.mode != modeGrid || len(e.rows) != 1 {
+		t.Errorf("mode %v, %d rows after cancelling the picker", e.mode, len(e.rows))
+	}
+}
+
+func TestHandleSaveAndQuit(t *testing.T) {
+	e := newEditor(testWeek())
+	if res := press(e, "s"); res != resultNone || e.message != "No changes to save" {
+		t.Errorf("save without changes: %v, %q", res, e.message)
+	}
+	if res := press(e, "q"); res != resultQuit {
+		t.Errorf("quit without changes = %v, want resultQuit", res)
+	}
+
+	e = newEditor(testWeek())
+	press(e, "\x1b[C2\r")
+	if res := press(e, "q"); res != resultNone || e.mode != modeDiscard {
+		t.Errorf("quit with changes = %v, mode %v, want the discard question", res, e.mode)
+	}
+	press(e, "n")
+	if e.mode != modeGrid {
+		t.Errorf("mode = %v after declining to discard, want modeGrid", e.mode)
+	}
+
+	e.week.Validate = func([]client.SaveEntry) error { return errors.New("over 24 hours") }
+	press(e, "s")
+	if e.mode != modeGrid || e.message != "Cannot save: over 24 hours" {
+		t.Errorf("save with invalid entries: mode %v, %q", e.mode, e.message)
+	}
+
+	e.week.Validate = nil
+	press(e, "s")
+	if e.mode != modeConfirm || len(e.changes) != 1 {
+		t.Fatalf("save: mode %v, %d changes, want confirmation of 1 change", e.mode, len(e.changes))
+	}
+	if res := press(e, "y"); res != resultSave {
+		t.Fatalf("confirm = %v, want resultSave", res)
+	}
+	entries := e.entries()
+	if len(entries) != 1 || entries[0].Days[1].Hours.String() != "2" {
+		t.Errorf("entries = %+v, want Tuesday 2", entries)
+	}
+}
+
+func TestEntriesLeaveOutEmptyRows(t *testing.T) {
+	e := newEditor(testWeek())
+	press(e, "asupport\r\x1b[A\x7f")
+	if entries := e.entries(); len(entries) != 0 {
+		t.Errorf("entries = %+v, want none", entries)
+	}
+}
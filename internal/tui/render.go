@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	cellWidth  = 11
+	totalWidth = 9
+	chromeRows = 10 // lines around the grid rows
+	reverse    = "\x1b[7m"
+	bold       = "\x1b[1m"
+	dim        = "\x1b[2m"
+	reset      = "\x1b[0m"
+)
+
+const gridHelp = "arrows move  0-9 hours  enter edit  n note  a add row  x remove row  s save  q quit"
+
+// render returns the screen as lines.
+func (e *editor) render() []string {
+	switch e.mode {
+	case modePicker:
+		return e.renderPicker()
+	case modeConfirm:
+		return e.renderChanges()
+	}
+	return e.renderGrid()
+}
+
+func (e *editor) nameWidth() int {
+	w := e.width - 7*cellWidth - totalWidth - 1
+	if w < 16 {
+		w = 16
+	}
+	return w
+}
+
+func (e *editor) visibleRows() int {
+	n := e.height - chromeRows
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func (e *editor) renderGrid() []string {
+	nameW := e.nameWidth()
+	lines := []string{
+		bold + fmt.Sprintf("Week of %s", e.week.StartDate) + reset + dim + "  " + e.week.Status + reset,
+		"",
+	}
+
+	header := fit("Project / Activity", nameW)
+	for _, label := range e.week.Labels {
+		header += fitRight(label, cellWidth)
+	}
+	header += fitRight("Total", totalWidth)
+	lines = append(lines, bold+header+reset, strings.Repeat("-", nameW+7*cellWidth+totalWidth))
+
+	// Keep the cursor row in view
+	visible := e.visibleRows()
+	if e.row < e.top {
+		e.top = e.row
+	}
+	if e.row >= e.top+visible {
+		e.top = e.row - visible + 1
+	}
+
+	var totals [7]float64
+	for i, r := range e.rows {
+		rowTotal := 0.0
+		for d, day := range r.Entry.Days {
+			totals[d] += day.Hours.Float()
+			rowTotal += day.Hours.Float()
+		}
+		if i < e.top || i >= e.top+visible {
+			continue
+		}
+
+		line := fit(r.label(), nameW)
+		if i == e.row {
+			line = bold + line + reset
+		}
+		for d, day := range r.Entry.Days {
+			text := day.Hours.String()
+			if i == e.row && d == e.col && e.mode == modeHours {
+				text = string(e.input) + "_"
+			} else if text == "" {
+				text = "-"
+			}
+			if strings.TrimSpace(day.Note) != "" {
+				text += "*"
+			} else {
+				text += " "
+			}
+			cell := fitRight(text, cellWidth)
+			if i == e.row && d == e.col {
+				cell = reverse + cell + reset
+			}
+			line += cell
+		}
+		lines = append(lines, line+fitRight(formatHours(rowTotal), totalWidth))
+	}
+	if len(e.rows) == 0 {
+		lines = append(lines, dim+"No entries; press a to add a row"+reset)
+	}
+
+	lines = append(lines, strings.Repeat("-", nameW+7*cellWidth+totalWidth))
+	footer := fit("Total", nameW)
+	weekTotal := 0.0
+	for _, t := range totals {
+		footer += fitRight(formatHours(t)+" ", cellWidth)
+		weekTotal += t
+	}
+	lines = append(lines, bold+footer+fitRight(formatHours(weekTotal), totalWidth)+reset, "")
+
+	switch {
+	case e.mode == modeNote:
+		lines = append(lines, "Note: "+string(e.input)+"_")
+	case e.day() != nil:
+		lines = append(lines, dim+"Note: "+reset+e.day().Note)
+	default:
+		lines = append(lines, "")
+	}
+
+	switch e.mode {
+	case modeDiscard:
+		lines = append(lines, bold+"Discard your changes? [y/N]"+reset)
+	case modeHours, modeNote:
+		lines = append(lines, dim+"enter keep  esc cancel"+reset)
+	default:
+		if e.message != "" {
+			lines = append(lines, bold+e.message+reset)
+		} else {
+			lines = append(lines, dim+gridHelp+reset)
+		}
+	}
+	return lines
+}
+
+func (e *editor) renderPicker() []string {
+	lines := []string{
+		bold + "Add a row" + reset + dim + "  type to search, enter to add, esc to cancel" + reset,
+		"",
+		"Search: " + string(e.query) + "_",
+		"",
+	}
+	room := e.height - len(lines) - 1
+	if room < 1 {
+		room = 1
+	}
+	first := 0
+	if e.pick >= room {
+		first = e.pick - room + 1
+	}
+	for i := first; i < len(e.matches) && i < first+room; i++ {
+		c := e.matches[i]
+		line := fit(c.label(), e.width-len(c.ProjectID)-len(c.ActivityID)-6) + dim + " " + c.ProjectID + "/" + c.ActivityID + reset
+		if i == e.pick {
+			line = reverse + line + reset
+		}
+		lines = append(lines, line)
+	}
+	if len(e.matches) == 0 {
+		lines = append(lines, dim+"No matching project or activity"+reset)
+	}
+	return lines
+}
+
+func (e *editor) renderChanges() []string {
+	lines := []string{bold + fmt.Sprintf("Save %d changed day(s) to TCRS?", len(e.changes)) + reset, ""}
+	names := make(map[string]string, len(e.rows))
+	for _, r := range e.week.Rows {
+		names[r.Entry.ProjectID+"$"+r.Entry.ActivityID] = r.label()
+	}
+	for _, r := range e.rows {
+		names[r.Entry.ProjectID+"$"+r.Entry.ActivityID] = r.label()
+	}
+
+	room := e.height - 4
+	for i, c := range e.changes {
+		if i >= room {
+			lines = append(lines, dim+fmt.Sprintf("... and %d more", len(e.changes)-i)+reset)
+			break
+		}
+		line := fit(names[c.ProjectID+"$"+c.ActivityID], 36) + " " + fit(e.week.Labels[c.Day], cellWidth) + " "
+		if c.Before.Hours.Float() != c.After.Hours.Float() {
+			line += fmt.Sprintf("%s -> %s", orDash(c.Before.Hours.String()), orDash(c.After.Hours.String()))
+		}
+		if strings.TrimSpace(c.Before.Note) != strings.TrimSpace(c.After.Note) {
+			line += fmt.Sprintf("  note %q -> %q", c.Before.Note, c.After.Note)
+		}
+		lines = append(lines, line)
+	}
+	return append(lines, "", bold+"[y] save  [n] back"+reset)
+}
+
+// formatHours formats a total, or "-" for none.
+func formatHours(h float64) string {
+	if h == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(math.Round(h*100)/100, 'f', -1, 64)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"unicode/utf8"
+
+	"github.com/user/tcrs/internal/textwidth"
+)
+
+// keyCode identifies a key press; keyRune carries a typed character.
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyTab
+	keyBacktab
+	keyBackspace
+	keyDelete
+	keyEsc
+	keyCtrlC
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// escapeKeys maps the escape sequences of common terminals to keys.
+var escapeKeys = map[string]keyCode{
+	"[A": keyUp, "[B": keyDown, "[C": keyRight, "[D": keyLeft,
+	"OA": keyUp, "OB": keyDown, "OC": keyRight, "OD": keyLeft,
+	"[H": keyHome, "[F": keyEnd, "OH": keyHome, "OF": keyEnd,
+	"[1~": keyHome, "[4~": keyEnd, "[7~": keyHome, "[8~": keyEnd,
+	"[5~": keyPageUp, "[6~": keyPageDown, "[3~": keyDelete, "[Z": keyBacktab,
+}
+
+// parseKeys decodes the bytes of one read from a raw-mode terminal. A
+// lone ESC is the Escape key; pasted text yields one key per character.
+func parseKeys(b []byte) []key {
+	var keys []key
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b:
+			if len(b) == 1 {
+				keys = append(keys, key{code: keyEsc})
+				return keys
+			}
+			seq, n := escapeSequence(b[1:])
+			if code, ok := escapeKeys[seq]; ok {
+				keys = append(keys, key{code: code})
+			} else if n == 0 {
+				keys = append(keys, key{code: keyEsc})
+			}
+			b = b[1+n:]
+			continue
+		case c == '\r' || c == '\n':
+			keys = append(keys, key{code: keyEnter})
+		case c == '\t':
+			keys = append(keys, key{code: keyTab})
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, key{code: keyBackspace})
+		case c == 0x03:
+			keys = append(keys, key{code: keyCtrlC})
+		case c < 0x20:
+			// other control characters are ignored
+		default:
+			r, n := utf8.DecodeRune(b)
+			keys = append(keys, key{code: keyRune, r: r})
+			b = b[n:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+// escapeSequence returns the CSI or SS3 sequence following an ESC and
+// its length, or "", 0 if none follows.
+func escapeSequence(b []byte) (string, int) {
+	if len(b) < 2 || (b[0] != '[' && b[0] != 'O') {
+		return "", 0
+	}
+	for i := 1; i < len(b); i++ {
+		if c := b[i]; c >= 0x40 && c <= 0x7e {
+			return string(b[:i+1]), i + 1
+		}
+	}
+	return "", 0
+}
+
+// fit truncates s to w columns, marking the cut with "~", and pads it
+// with spaces on the right.
+func fit(s string, w int) string {
+	if w <= 0 {
+		return ""
+	}
+	return textwidth.Pad(textwidth.Truncate(s, w, "~"), w)
+}
+
+// fitRight is fit with the padding on the left.
+func fitRight(s string, w int) string {
+	if w <= 0 {
+		return ""
+	}
+	return textwidth.PadLeft(textwidth.Truncate(s, w, "~"), w)
+}
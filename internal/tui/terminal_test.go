@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []key
+	}{
+		{"", nil},
+		{"a7", []key{{code: keyRune, r: 'a'}, {code: keyRune, r: '7'}}},
+		{"資料", []key{{code: keyRune, r: '資'}, {code: keyRune, r: '料'}}},
+		{"\r\n\t", []key{{code: keyEnter}, {code: keyEnter}, {code: keyTab}}},
+		{"\x7f\x08\x03", []key{{code: keyBackspace}, {code: keyBackspace}, {code: keyCtrlC}}},
+		{"\x01\x1a", nil},
+		{"\x1b", []key{{code: keyEsc}}},
+		{"\x1b[A\x1b[B\x1b[C\x1b[D", []key{{code: keyUp}, {code: keyDown}, {code: keyRight}, {code: keyLeft}}},
+		{"\x1bOA\x1bOH\x1bOF", []key{{code: keyUp}, {code: keyHome}, {code: keyEnd}}},
+		{"\x1b[1~\x1b[4~\x1b[5~\x1b[6~\x1b[3~\x1b[Z", []key{
+			{code: keyHome}, {code: keyEnd}, {code: keyPageUp}, {code: keyPageDown}, {code: keyDelete}, {code: keyBacktab}}},
+		// unknown sequences are dropped whole
+		{"\x1b[1;5Ca", []key{{code: keyRune, r: 'a'}}},
+		// ESC followed by a character is Escape, then the character
+		{"\x1bq", []key{{code: keyEsc}, {code: keyRune, r: 'q'}}},
+		// an unfinished sequence is Escape followed by its characters
+		{"\x1b[", []key{{code: keyEsc}, {code: keyRune, r: '['}}},
+	}
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseKeys(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		s    string
+		w    int
+		want string
+	}{
+		{"abc", 5, "abc  "},
+		{"abcdef", 5, "abcd~"},
+		{"abcde", 5, "abcde"},
+		{"abc", 0, ""},
+		{"abc", -1, ""},
+		{"資訊系統", 8, "資訊系統"},
+		{"資訊系統", 6, "資訊~ "},
+		{"資訊系統", 5, "資訊~"},
+		{"資訊", 6, "資訊  "},
+	}
+	for _, tt := range tests {
+		if got := fit(tt.s, tt.w); got != tt.want {
+			t.Errorf("fit(%q, %d) = %q, want %q", tt.s, tt.w, got, tt.want)
+		}
+	}
+
+	if got := fitRight("7.5", 5); got != "  7.5" {
+		t.Errorf("fitRight(%q, 5) = %q, want %q", "7.5", got, "  7.5")
+	}
+	if got := fitRight("資訊系統", 6); got != " 資訊~" {
+		t.Errorf("fitRight(%q, 6) = %q, want %q", "資訊系統", got, " 資訊~")
+	}
+}
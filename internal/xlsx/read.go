@@ -0,0 +1,309 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadFirstSheet returns the cells of the first worksheet of a workbook
+// as text, row by row, with rows at the index of their spreadsheet row so
+// that blank rows the file leaves out are kept as empty rows. Numbers are
+// returned as written in the file, so dates appear as serial numbers (see
+// SerialDate), except cells formatted as a time or duration, which are
+// returned as H:MM. Empty cells are "".
+func ReadFirstSheet(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not an XLSX file: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := sharedStrings(files)
+	if err != nil {
+		return nil, err
+	}
+	timeStyles, err := timeStyles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("worksheet %s not found", sheetPath)
+	}
+	var ws struct {
+		Rows []struct {
+			Ref   string `xml:"r,attr"`
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Style  string `xml:"s,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline rich   `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeXML(f, &ws); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(ws.Rows))
+	for _, row := range ws.Rows {
+		if row.Ref != "" {
+			n, err := strconv.Atoi(strings.TrimSpace(row.Ref))
+			if err != nil || n < len(rows)+1 {
+				return nil, fmt.Errorf("invalid row number %q", row.Ref)
+			}
+			for len(rows) < n-1 {
+				rows = append(rows, nil)
+			}
+		}
+
+		var cells []string
+		for i, c := range row.Cells {
+			col := i
+			if c.Ref != "" {
+				if col, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+
+			value := c.Value
+			switch c.Type {
+			case "s":
+				idx, err := strconv.Atoi(strings.TrimSpace(c.Value))
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("cell %s: invalid shared string %q", c.Ref, c.Value)
+				}
+				value = shared[idx]
+			case "inlineStr":
+				value = c.Inline.text()
+			case "", "n":
+				if style, err := strconv.Atoi(c.Style); err == nil && style < len(timeStyles) && timeStyles[style] {
+					value = clockValue(c.Value)
+				}
+			}
+			cells[col] = value
+		}
+		rows = append(rows, cells)
+	}
+	return rows, nil
+}
+
+// SerialDate converts a spreadsheet date serial number (days since
+// 1899-12-30) to a date.
+func SerialDate(serial float64) time.Time {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return epoch.AddDate(0, 0, int(serial))
+}
+
+// clockValue formats a time or duration cell, a fraction of a day, as
+// H:MM, or returns it unchanged if it is not a number.
+func clockValue(v string) string {
+	days, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || days < 0 {
+		return v
+	}
+	minutes := int(math.Round(days * 24 * 60))
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// timeStyles reports for each cell style of the workbook whether its
+// number format shows a time or duration without a date.
+func timeStyles(files map[string]*zip.File) ([]bool, error) {
+	f, ok := files["xl/styles.xml"]
+	if !ok {
+		return nil, nil
+	}
+	var ss struct {
+		NumFmts []struct {
+			ID   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		CellXfs []struct {
+			NumFmtID int `xml:"numFmtId,attr"`
+		} `xml:"cellXfs>xf"`
+	}
+	if err := decodeXML(f, &ss); err != nil {
+		return nil, err
+	}
+	codes := make(map[int]string, len(ss.NumFmts))
+	for _, nf := range ss.NumFmts {
+		codes[nf.ID] = nf.Code
+	}
+	styles := make([]bool, len(ss.CellXfs))
+	for i, xf := range ss.CellXfs {
+		if code, ok := codes[xf.NumFmtID]; ok {
+			styles[i] = isTimeFormat(code)
+		} else {
+			styles[i] = builtinTimeFormats[xf.NumFmtID]
+		}
+	}
+	return styles, nil
+}
+
+// builtinTimeFormats are the built-in number formats showing only a time:
+// h:mm AM/PM, h:mm:ss AM/PM, h:mm, h:mm:ss, mm:ss, [h]:mm:ss and mm:ss.0.
+var builtinTimeFormats = map[int]bool{18: true, 19: true, 20: true, 21: true, 45: true, 46: true, 47: true}
+
+// isTimeFormat reports whether a custom number format shows hours or
+// seconds but no date. Quoted text, escaped characters and bracketed
+// colors and locales are ignored; elapsed time such as [h] counts.
+func isTimeFormat(code string) bool {
+	hasTime, hasDate := false, false
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; c {
+		case '"':
+			for i++; i < len(code) && code[i] != '"'; i++ {
+			}
+		case '\\':
+			i++
+		case '[':
+			end := strings.IndexByte(code[i:], ']')
+			if end < 0 {
+				return false
+			}
+			switch strings.ToLower(code[i+1 : i+end]) {
+			case "h", "hh", "m", "mm", "s", "ss":
+				hasTime = true
+			}
+			i += end
+		case 'h', 'H', 's', 'S':
+			hasTime = true
+		case 'y', 'Y', 'd', 'D':
+			hasDate = true
+		}
+	}
+	return hasTime && !hasDate
+}
+
+// rich is a string item that is either plain or made of formatted runs.
+type rich struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r rich) text() string {
+	if len(r.Runs) == 0 {
+		return r.Text
+	}
+	var b strings.Builder
+	for _, run := range r.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// firstSheetPath finds the part holding the workbook's first sheet.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	wbFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("not an XLSX file: xl/workbook.xml missing")
+	}
+	var wb struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeXML(wbFile, &wb); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", fmt.Errorf("workbook has no sheets")
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeXML(relsFile, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Rels {
+		if rel.ID == wb.Sheets[0].RID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+// sharedStrings reads the workbook's shared string table, if any.
+func sharedStrings(files map[string]*zip.File) ([]string, error) {
+	f, ok := files["xl/sharedStrings.xml"]
+	if !ok {
+		return nil, nil
+	}
+	var sst struct {
+		Items []rich `xml:"si"`
+	}
+	if err := decodeXML(f, &sst); err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		strs[i] = item.text()
+	}
+	return strs, nil
+}
+
+func decodeXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex returns the zero-based column of an A1-style reference.
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A') + 1
+		n++
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return col - 1, nil
+}
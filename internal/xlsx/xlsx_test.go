@@ -0,0 +1,269 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	sheets := []Sheet{
+		{Name: "Entries & <Totals>", Rows: [][]interface{}{
+			{"date", "project", "hours", "note"},
+			{45670.0, "資訊系統開發", 7.5, "review <PR> & fix"},
+			{45671.0, "Alpha", 8, "line one\nline two\ttabbed"},
+			{45672.0, nil, 0.25, ""},
+			{"", "Beta", 1.0, "bell\x07 and\x00 nul\x1f"},
+		}},
+		{Name: "Second", Rows: [][]interface{}{{"only"}}},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, sheets); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	rows, err := ReadFirstSheet(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadFirstSheet: %v", err)
+	}
+	want := [][]string{
+		{"date", "project", "hours", "note"},
+		{"45670", "資訊系統開發", "7.5", "review <PR> & fix"},
+		{"45671", "Alpha", "8", "line one\nline two\ttabbed"},
+		{"45672", "", "0.25"},
+		{"", "Beta", "1", "bell and nul"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
+
+func TestWriteUnsupportedCell(t *testing.T) {
+	err := Write(&bytes.Buffer{}, []Sheet{{Name: "S", Rows: [][]interface{}{{true}}}})
+	if err == nil || !strings.Contains(err.Error(), "unsupported cell type bool at A1") {
+		t.Errorf("err = %v, want unsupported cell type", err)
+	}
+}
+
+func TestEscapeDropsIllegalCharacters(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"a&b<c>", "a&amp;b&lt;c&gt;"},
+		{"x\x01y\x0bz\x1f", "xyz"},
+		{"tab\tlf\ncr\r", "tab&#x9;lf&#xA;cr&#xD;"},
+		{"中文", "中文"},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// workbookZip builds a workbook from the given parts, adding the
+// workbook and its relationships pointing sheet 1 at sheetPath.
+func workbookZip(t *testing.T, sheetPath string, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Data" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId7" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="` + sheetPath + `"/>` +
+			`</Relationships>`,
+	}
+	for name, body := range parts {
+		files[name] = body
+	}
+	for name, body := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadFirstSheet(t *testing.T) {
+	const ns = `xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"`
+	shared := `<sst ` + ns + `><si><t>date</t></si><si><t>hours</t></si>` +
+		`<si><r><t>Rich </t></r><r><rPr><b/></rPr><t>text</t></r></si></sst>`
+	styles := `<styleSheet ` + ns + `><numFmts count="3">` +
+		`<numFmt numFmtId="164" formatCode="[h]:mm"/>` +
+		`<numFmt numFmtId="165" formatCode="yyyy/m/d"/>` +
+		`<numFmt numFmtId="166" formatCode="[$-404]e/m/d h:mm"/>` +
+		`</numFmts><cellXfs count="5"><xf numFmtId="0"/><xf numFmtId="164"/><xf numFmtId="165"/><xf numFmtId="20"/><xf numFmtId="166"/></cellXfs></styleSheet>`
+
+	tests := []struct {
+		name      string
+		sheetPath string
+		parts     map[string]string
+		want      [][]string
+	}{
+		{
+			name:      "shared strings and rich text",
+			sheetPath: "worksheets/data.xml",
+			parts: map[string]string{
+				"xl/sharedStrings.xml": shared,
+				"xl/worksheets/data.xml": `<worksheet ` + ns + `><sheetData>` +
+					`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+					`<row r="2"><c r="A2"><v>45670</v></c><c r="C2" t="s"><v>2</v></c></row>` +
+					`</sheetData></worksheet>`,
+			},
+			want: [][]string{{"date", "hours"}, {"45670", "", "Rich text"}},
+		},
+		{
+			name:      "absolute relationship target",
+			sheetPath: "/xl/worksheets/sheet9.xml",
+			parts: map[string]string{
+				"xl/worksheets/sheet9.xml": `<worksheet ` + ns + `><sheetData>` +
+					`<row r="1"><c r="AA1" t="inlineStr"><is><t>far</t></is></c></row>` +
+					`</sheetData></worksheet>`,
+			},
+			want: [][]string{append(make([]string, 26), "far")},
+		},
+		{
+			name:      "omitted blank rows keep their place",
+			sheetPath: "worksheets/sheet1.xml",
+			parts: map[string]string{
+				"xl/worksheets/sheet1.xml": `<worksheet ` + ns + `><sheetData>` +
+					`<row r="2"><c r="A2" t="inlineStr"><is><t>header</t></is></c></row>` +
+					`<row r="5"><c r="B5"><v>1</v></c></row>` +
+					`<row><c><v>2</v></c></row>` +
+					`</sheetData></worksheet>`,
+			},
+			want: [][]string{nil, {"header"}, nil, nil, {"", "1"}, {"2"}},
+		},
+		{
+			name:      "time formatted cells",
+			sheetPath: "worksheets/sheet1.xml",
+			parts: map[string]string{
+				"xl/styles.xml": styles,
+				"xl/worksheets/sheet1.xml": `<worksheet ` + ns + `><sheetData><row r="1">` +
+					`<c r="A1" s="1"><v>0.3125</v></c>` +
+					`<c r="B1" s="3"><v>1.5</v></c>` +
+					`<c r="C1" s="2"><v>45670</v></c>` +
+					`<c r="D1" s="4"><v>45670.5</v></c>` +
+					`<c r="E1"><v>0.3125</v></c>` +
+					`<c r="F1" s="1" t="s"><v>0</v></c>` +
+					`</row></sheetData></worksheet>`,
+				"xl/sharedStrings.xml": shared,
+			},
+			want: [][]string{{"7:30", "36:00", "45670", "45670.5", "0.3125", "date"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ReadFirstSheet(workbookZip(t, tt.sheetPath, tt.parts))
+			if err != nil {
+				t.Fatalf("ReadFirstSheet: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("rows = %q, want %q", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFirstSheetErrors(t *testing.T) {
+	const ns = `xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"`
+	tests := []struct {
+		name  string
+		sheet string
+		want  string
+	}{
+		{"bad shared string", `<row r="1"><c r="A1" t="s"><v>3</v></c></row>`, "invalid shared string"},
+		{"rows out of order", `<row r="3"/><row r="2"/>`, "invalid row number"},
+		{"bad cell reference", `<row r="1"><c r="1A"><v>1</v></c></row>`, "invalid cell reference"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := workbookZip(t, "worksheets/sheet1.xml", map[string]string{
+				"xl/worksheets/sheet1.xml": `<worksheet ` + ns + `><sheetData>` + tt.sheet + `</sheetData></worksheet>`,
+			})
+			_, err := ReadFirstSheet(data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ReadFirstSheet([]byte("date,hours\n")); err == nil {
+		t.Error("ReadFirstSheet of CSV data succeeded")
+	}
+}
+
+func TestCellRef(t *testing.T) {
+	tests := []struct {
+		col, row int
+		want     string
+	}{
+		{0, 0, "A1"},
+		{25, 9, "Z10"},
+		{26, 0, "AA1"},
+		{51, 0, "AZ1"},
+		{52, 0, "BA1"},
+		{701, 0, "ZZ1"},
+		{702, 99, "AAA100"},
+	}
+	for _, tt := range tests {
+		got := CellRef(tt.col, tt.row)
+		if got != tt.want {
+			t.Errorf("CellRef(%d, %d) = %s, want %s", tt.col, tt.row, got, tt.want)
+		}
+		if col, err := columnIndex(got); err != nil || col != tt.col {
+			t.Errorf("columnIndex(%s) = %d, %v, want %d", got, col, err, tt.col)
+		}
+	}
+}
+
+func TestSerialDate(t *testing.T) {
+	tests := []struct {
+		serial float64
+		want   string
+	}{
+		{1, "1899-12-31"},
+		{61, "1900-03-01"},
+		{45658, "2025-01-01"},
+		{45670.75, "2025-01-13"},
+	}
+	for _, tt := range tests {
+		if got := SerialDate(tt.serial).Format(time.DateOnly); got != tt.want {
+			t.Errorf("SerialDate(%v) = %s, want %s", tt.serial, got, tt.want)
+		}
+	}
+}
+
+func TestIsTimeFormat(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"h:mm", true},
+		{"[h]:mm:ss", true},
+		{"[mm]:ss", true},
+		{"hh:mm AM/PM", true},
+		{"[Red][h]:mm", true},
+		{"yyyy-mm-dd", false},
+		{"m/d/yy h:mm", false},
+		{"0.00", false},
+		{`0.0 "hours"`, false},
+		{`0\h`, false},
+		{"General", false},
+	}
+	for _, tt := range tests {
+		if got := isTimeFormat(tt.code); got != tt.want {
+			t.Errorf("isTimeFormat(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}